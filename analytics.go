@@ -0,0 +1,391 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/config"
+	"github.com/deroproject/derohe/transaction"
+)
+
+// What the analytics need of a topo block
+type BlockSample struct {
+	topo       int64
+	height     int64
+	timestamp  uint64 // milliseconds
+	difficulty uint64
+	miniblocks int
+	side       bool // lost the race for its height, it is paid but does not extend the chain
+	reward     uint64
+	integrator [33]byte   // compressed key paid for the final miniblock
+	keys       [][16]byte // key hashes of the miners of the other miniblocks
+}
+
+// Recent topo blocks of the local chain in topo order, newest are loaded first so
+// small windows are ready straight away while older blocks fill in behind
+type Analytics struct {
+	sync.Mutex
+	network string
+	samples []BlockSample
+	floor   int64 // oldest topo the store still holds
+}
+
+// Block figures over a window of recent topo blocks
+type BlockStats struct {
+	blocks       int
+	side         int
+	side_rate    float64 // percent of blocks
+	mean         float64 // seconds between main chain blocks
+	median       float64
+	p95          float64
+	miniblocks   float64 // per main chain block
+	difficulty   uint64  // latest
+	hashrate     float64 // network estimate in H/s
+	trend        float64 // percent change of the average difficulty from the older half of the window to the newer
+	difficulties []float64
+	histogram    []int
+}
+
+const (
+	ANALYTICS_MAX         = 5000
+	ANALYTICS_BATCH       = 1000
+	ANALYTICS_REORG_DEPTH = 20
+	ANALYTICS_BIN_SECONDS = 3
+	ANALYTICS_BINS        = 13 // the last bin holds everything slower
+	STATUS_WINDOW         = 50
+	HASHRATE_WINDOW       = 100
+	TARGET_BLOCK_TIME     = config.BLOCK_TIME
+
+	// Hashes expected per block in units of its difficulty, every miniblock meets the
+	// difficulty and the final one is mined at MINIBLOCK_HIGHDIFF times it
+	BLOCK_WORK = MINIBLOCKS_PER_BLOCK - 1 + config.MINIBLOCK_HIGHDIFF
+)
+
+var analyticsWindows = map[string]int{
+	"50":   50,
+	"200":  200,
+	"1000": 1000,
+	"5000": 5000,
+}
+
+var analytics Analytics
+
+// Load new topo blocks and extend the history backwards, bounded per call
+func (a *Analytics) update(chain *blockchain.Blockchain) {
+	a.Lock()
+	defer a.Unlock()
+
+	top := chain.Load_TOPO_HEIGHT()
+
+	if a.network != networkName() || (len(a.samples) > 0 && a.samples[0].topo > top) {
+		a.network = networkName()
+		a.samples = nil
+		a.floor = 0
+	}
+
+	// Recent blocks may have been reordered, drop them and load again
+	from := top - ANALYTICS_REORG_DEPTH
+	if len(a.samples) > 0 {
+		if last := a.samples[len(a.samples)-1].topo + 1; last < from {
+			from = last
+		}
+	}
+	if from < top-ANALYTICS_BATCH+1 {
+		// Too far behind to catch up, start over from the top
+		a.samples = nil
+		a.floor = 0
+		from = top - ANALYTICS_BATCH + 1
+	}
+	if from < 1 {
+		from = 1
+	}
+
+	for len(a.samples) > 0 && a.samples[len(a.samples)-1].topo >= from {
+		a.samples = a.samples[:len(a.samples)-1]
+	}
+
+	for t := from; t <= top; t++ {
+		s, ok := loadBlockSample(chain, t)
+		if !ok {
+			a.samples = nil
+			a.floor = t
+			continue
+		}
+		a.samples = append(a.samples, s)
+	}
+
+	if len(a.samples) == 0 || len(a.samples) >= ANALYTICS_MAX {
+		a.samples = trimSamples(a.samples, ANALYTICS_MAX)
+		return
+	}
+
+	var older []BlockSample
+	first := a.samples[0].topo
+	for t := first - 1; t >= 1 && t > a.floor && len(older)+len(a.samples) < ANALYTICS_MAX && len(older) < ANALYTICS_BATCH; t-- {
+		s, ok := loadBlockSample(chain, t)
+		if !ok {
+			a.floor = t
+			break
+		}
+		older = append(older, s)
+	}
+
+	if len(older) > 0 {
+		for i, j := 0, len(older)-1; i < j; i, j = i+1, j-1 {
+			older[i], older[j] = older[j], older[i]
+		}
+		a.samples = append(older, a.samples...)
+	}
+}
+
+func trimSamples(samples []BlockSample, n int) []BlockSample {
+	if len(samples) > n {
+		return append([]BlockSample(nil), samples[len(samples)-n:]...)
+	}
+
+	return samples
+}
+
+// Same consensus rule derod applies, a block at the height of the previous topo block is a side block
+func isSideBlock(chain *blockchain.Blockchain, topo int64, height int64) bool {
+	previous, err := chain.Store.Topo_store.Read(topo - 1)
+
+	return err == nil && previous.Height == height
+}
+
+// Read a topo block, pruned and fast synced chains do not hold all of them
+func loadBlockSample(chain *blockchain.Blockchain, topo int64) (s BlockSample, ok bool) {
+	record, err := chain.Store.Topo_store.Read(topo)
+	if err != nil || record.BLOCK_ID == [32]byte{} {
+		return
+	}
+
+	bl, err := chain.Load_BL_FROM_ID(record.BLOCK_ID)
+	if err != nil {
+		return
+	}
+
+	s.topo = topo
+	s.height = record.Height
+	s.timestamp = bl.Timestamp
+	s.miniblocks = len(bl.MiniBlocks)
+	s.integrator = bl.Miner_TX.MinerAddress
+
+	for _, mbl := range bl.MiniBlocks {
+		if !mbl.Final {
+			var key [16]byte
+			copy(key[:], mbl.KeyHash[:16])
+			s.keys = append(s.keys, key)
+		}
+	}
+
+	// Smart contract calls are charged gas rather than their fee, close enough for reward figures
+	s.reward = blockchain.CalcBlockReward(uint64(record.Height))
+	for _, txid := range bl.Tx_hashes {
+		if data, err := chain.Store.Block_tx_store.ReadTX(txid); err == nil {
+			var tx transaction.Transaction
+			if tx.Deserialize(data) == nil {
+				s.reward += tx.Fees()
+			}
+		}
+	}
+
+	if diff, err := chain.Store.Block_tx_store.ReadBlockDifficulty(record.BLOCK_ID); err == nil {
+		s.difficulty = diff.Uint64()
+	}
+
+	s.side = isSideBlock(chain, topo, record.Height)
+
+	return s, true
+}
+
+// Stats over the newest window blocks
+func (a *Analytics) stats(window int) BlockStats {
+	a.Lock()
+	defer a.Unlock()
+
+	return blockStats(trimSamples(a.samples, window))
+}
+
+func blockStats(samples []BlockSample) (s BlockStats) {
+	s.blocks = len(samples)
+	s.histogram = make([]int, ANALYTICS_BINS)
+
+	var main []BlockSample
+	for _, b := range samples {
+		if b.side {
+			s.side++
+		} else {
+			main = append(main, b)
+		}
+	}
+
+	if s.blocks > 0 {
+		s.side_rate = float64(s.side) / float64(s.blocks) * 100
+	}
+
+	var intervals []float64
+	for i := 1; i < len(main); i++ {
+		prev, cur := main[i-1], main[i]
+		if cur.height <= prev.height || cur.timestamp < prev.timestamp {
+			continue
+		}
+
+		// A gap of pruned blocks is spread over the heights it covers
+		interval := float64(cur.timestamp-prev.timestamp) / 1000 / float64(cur.height-prev.height)
+		intervals = append(intervals, interval)
+
+		bin := int(interval / ANALYTICS_BIN_SECONDS)
+		if bin >= ANALYTICS_BINS {
+			bin = ANALYTICS_BINS - 1
+		}
+		s.histogram[bin]++
+	}
+
+	if len(intervals) > 0 {
+		sum := 0.0
+		for _, v := range intervals {
+			sum += v
+		}
+		s.mean = sum / float64(len(intervals))
+
+		sort.Float64s(intervals)
+		s.median = percentile(intervals, 50)
+		s.p95 = percentile(intervals, 95)
+	}
+
+	if len(main) > 0 {
+		total := 0
+		for _, b := range main {
+			total += b.miniblocks
+			s.difficulties = append(s.difficulties, float64(b.difficulty))
+		}
+		s.miniblocks = float64(total) / float64(len(main))
+		s.difficulty = main[len(main)-1].difficulty
+	}
+
+	s.hashrate = networkHashrate(samples)
+
+	if half := len(s.difficulties) / 2; half > 0 {
+		older, newer := average(s.difficulties[:half]), average(s.difficulties[half:])
+		if older > 0 {
+			s.trend = (newer - older) / older * 100
+		}
+	}
+
+	return
+}
+
+// Work of the blocks after the first over the time they took, side blocks were mined
+// too so their work counts
+func networkHashrate(samples []BlockSample) float64 {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	first, last := samples[0].timestamp, samples[0].timestamp
+	work := 0.0
+	for i, b := range samples {
+		if b.timestamp < first {
+			first = b.timestamp
+		}
+		if b.timestamp > last {
+			last = b.timestamp
+		}
+		if i > 0 {
+			work += float64(b.difficulty) * float64(BLOCK_WORK)
+		}
+	}
+
+	if last <= first {
+		return 0
+	}
+
+	return work / (float64(last-first) / 1000)
+}
+
+// Percent of the network hashrate that is ours
+func hashrateShare(local float64, network float64) float64 {
+	if network <= 0 || local <= 0 {
+		return 0
+	}
+
+	return local / network * 100
+}
+
+// Expected wait for one of our miniblocks, the network finds MINIBLOCKS_PER_BLOCK every block time
+func miniblockETA(local float64, network float64) time.Duration {
+	if network <= 0 || local <= 0 {
+		return 0
+	}
+
+	seconds := float64(TARGET_BLOCK_TIME) / float64(MINIBLOCKS_PER_BLOCK) * network / local
+
+	return time.Duration(seconds * float64(time.Second))
+}
+
+// Coarse duration for display, seconds do not matter at these scales
+func formatETA(d time.Duration) string {
+	switch {
+	case d <= 0:
+		return "---"
+	case d < time.Minute:
+		return "< 1m"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh %dm", int(d.Hours()), int(d.Minutes())%60)
+	}
+
+	return fmt.Sprintf("%dd %dh", int(d.Hours())/24, int(d.Hours())%24)
+}
+
+// Nearest rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+
+	return sorted[rank-1]
+}
+
+func average(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+// Histogram bin holding the target block time
+func targetBin() int {
+	return int(TARGET_BLOCK_TIME / ANALYTICS_BIN_SECONDS)
+}
@@ -0,0 +1,94 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/deroproject/derohe/globals"
+)
+
+const AUTOSTART_FILE = "netrunner.desktop"
+
+// XDG autostart entry in the user config directory
+func autostartPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "autostart", AUTOSTART_FILE), nil
+}
+
+func launchAtLogin() bool {
+	path, err := autostartPath()
+	if err != nil {
+		return false
+	}
+
+	_, err = os.Stat(path)
+
+	return err == nil
+}
+
+func setLaunchAtLogin(enable bool) error {
+	path, err := autostartPath()
+	if err != nil {
+		return err
+	}
+
+	if !enable {
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+
+		globals.Logger.Info("[Netrunner] Launch at login disabled")
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if exe, err = filepath.EvalSymlinks(exe); err != nil {
+		return err
+	}
+
+	entry := fmt.Sprintf("[Desktop Entry]\nType=Application\nName=Netrunner\nComment=DERO node and miner\nExec=%s\nTerminal=false\nX-GNOME-Autostart-enabled=true\n", desktopQuote(exe))
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, []byte(entry), 0644); err != nil {
+		return err
+	}
+
+	globals.Logger.Info("[Netrunner] Launch at login enabled", "path", path)
+
+	return nil
+}
+
+// Quote an Exec argument as the desktop entry spec requires
+func desktopQuote(s string) string {
+	r := strings.NewReplacer(`\`, `\\\\`, `"`, `\\"`, "`", "\\\\`", `$`, `\\$`)
+
+	return `"` + r.Replace(s) + `"`
+}
@@ -0,0 +1,70 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"os"
+
+	"github.com/deroproject/derohe/globals"
+	"golang.org/x/sys/windows/registry"
+)
+
+const (
+	AUTOSTART_KEY   = `Software\Microsoft\Windows\CurrentVersion\Run`
+	AUTOSTART_VALUE = "Netrunner"
+)
+
+func launchAtLogin() bool {
+	k, err := registry.OpenKey(registry.CURRENT_USER, AUTOSTART_KEY, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer k.Close()
+
+	_, _, err = k.GetStringValue(AUTOSTART_VALUE)
+
+	return err == nil
+}
+
+func setLaunchAtLogin(enable bool) error {
+	k, _, err := registry.CreateKey(registry.CURRENT_USER, AUTOSTART_KEY, registry.SET_VALUE)
+	if err != nil {
+		return err
+	}
+	defer k.Close()
+
+	if !enable {
+		if err = k.DeleteValue(AUTOSTART_VALUE); err != nil && err != registry.ErrNotExist {
+			return err
+		}
+
+		globals.Logger.Info("[Netrunner] Launch at login disabled")
+		return nil
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	if err = k.SetStringValue(AUTOSTART_VALUE, `"`+exe+`"`); err != nil {
+		return err
+	}
+
+	globals.Logger.Info("[Netrunner] Launch at login enabled", "path", exe)
+
+	return nil
+}
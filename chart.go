@@ -0,0 +1,230 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"image/color"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+)
+
+// Minimal line chart, the series is scaled to fill the widget
+type Chart struct {
+	widget.BaseWidget
+	points []float64
+	color  color.Color
+}
+
+type chartRenderer struct {
+	chart      *Chart
+	background *canvas.Rectangle
+	lines      []*canvas.Line
+	objects    []fyne.CanvasObject
+}
+
+const CHART_HEIGHT = 80
+
+func NewChart(c color.Color) *Chart {
+	chart := &Chart{color: c}
+	chart.ExtendBaseWidget(chart)
+
+	return chart
+}
+
+func (c *Chart) SetPoints(points []float64) {
+	c.points = points
+	c.Refresh()
+}
+
+func (c *Chart) CreateRenderer() fyne.WidgetRenderer {
+	r := &chartRenderer{chart: c, background: canvas.NewRectangle(colors.darkmatter)}
+	r.rebuild()
+
+	return r
+}
+
+// One line per segment, recreated only when the number of points changes
+func (r *chartRenderer) rebuild() {
+	n := len(r.chart.points) - 1
+	if n < 0 {
+		n = 0
+	}
+
+	if len(r.lines) != n {
+		r.lines = make([]*canvas.Line, n)
+		for i := range r.lines {
+			r.lines[i] = canvas.NewLine(r.chart.color)
+			r.lines[i].StrokeWidth = 1.5
+		}
+	}
+
+	r.objects = []fyne.CanvasObject{r.background}
+	for _, l := range r.lines {
+		r.objects = append(r.objects, l)
+	}
+}
+
+func (r *chartRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+
+	points := r.chart.points
+	if len(points) < 2 {
+		return
+	}
+
+	min, max := points[0], points[0]
+	for _, p := range points {
+		if p < min {
+			min = p
+		}
+		if p > max {
+			max = p
+		}
+	}
+
+	pad := float32(4)
+	step := (size.Width - 2*pad) / float32(len(points)-1)
+	y := func(v float64) float32 {
+		if max == min {
+			return size.Height / 2
+		}
+		return pad + (size.Height-2*pad)*float32((max-v)/(max-min))
+	}
+
+	for i, l := range r.lines {
+		l.Position1 = fyne.NewPos(pad+float32(i)*step, y(points[i]))
+		l.Position2 = fyne.NewPos(pad+float32(i+1)*step, y(points[i+1]))
+	}
+}
+
+func (r *chartRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, CHART_HEIGHT)
+}
+
+func (r *chartRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.chart.Size())
+	for _, o := range r.objects {
+		o.Refresh()
+	}
+}
+
+func (r *chartRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *chartRenderer) Destroy() {}
+
+// Bar chart of counts, one bin can be marked in a second color
+type Histogram struct {
+	widget.BaseWidget
+	bins   []int
+	mark   int
+	color  color.Color
+	marked color.Color
+}
+
+type histogramRenderer struct {
+	histogram  *Histogram
+	background *canvas.Rectangle
+	bars       []*canvas.Rectangle
+	objects    []fyne.CanvasObject
+}
+
+func NewHistogram(c color.Color, marked color.Color, mark int) *Histogram {
+	h := &Histogram{color: c, marked: marked, mark: mark}
+	h.ExtendBaseWidget(h)
+
+	return h
+}
+
+func (h *Histogram) SetBins(bins []int) {
+	h.bins = bins
+	h.Refresh()
+}
+
+func (h *Histogram) CreateRenderer() fyne.WidgetRenderer {
+	r := &histogramRenderer{histogram: h, background: canvas.NewRectangle(colors.darkmatter)}
+	r.rebuild()
+
+	return r
+}
+
+func (r *histogramRenderer) rebuild() {
+	if len(r.bars) != len(r.histogram.bins) {
+		r.bars = make([]*canvas.Rectangle, len(r.histogram.bins))
+		for i := range r.bars {
+			c := r.histogram.color
+			if i == r.histogram.mark {
+				c = r.histogram.marked
+			}
+			r.bars[i] = canvas.NewRectangle(c)
+		}
+	}
+
+	r.objects = []fyne.CanvasObject{r.background}
+	for _, b := range r.bars {
+		r.objects = append(r.objects, b)
+	}
+}
+
+func (r *histogramRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+
+	bins := r.histogram.bins
+	if len(bins) == 0 {
+		return
+	}
+
+	max := 0
+	for _, n := range bins {
+		if n > max {
+			max = n
+		}
+	}
+
+	pad := float32(4)
+	step := (size.Width - 2*pad) / float32(len(bins))
+	for i, b := range r.bars {
+		height := float32(0)
+		if max > 0 {
+			height = (size.Height - 2*pad) * float32(bins[i]) / float32(max)
+		}
+
+		b.Move(fyne.NewPos(pad+float32(i)*step+1, size.Height-pad-height))
+		b.Resize(fyne.NewSize(step-2, height))
+	}
+}
+
+func (r *histogramRenderer) MinSize() fyne.Size {
+	return fyne.NewSize(200, CHART_HEIGHT)
+}
+
+func (r *histogramRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.histogram.Size())
+	for _, o := range r.objects {
+		o.Refresh()
+	}
+}
+
+func (r *histogramRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *histogramRenderer) Destroy() {}
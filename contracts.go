@@ -0,0 +1,277 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/deroproject/derohe/cmd/derod/rpc"
+	"github.com/deroproject/derohe/cryptography/crypto"
+	"github.com/deroproject/derohe/globals"
+	derorpc "github.com/deroproject/derohe/rpc"
+)
+
+// Smart contract as stored on the local chain at its current topo height
+type Contract struct {
+	scid      string
+	code      string
+	balance   uint64
+	balances  []ContractBalance
+	variables []ContractVariable
+	functions []ContractFunction
+}
+
+type ContractBalance struct {
+	token  string
+	amount uint64
+}
+
+func (b ContractBalance) name() string {
+	if b.token == crypto.ZEROHASH.String() {
+		return "DERO"
+	}
+
+	return b.token
+}
+
+type ContractVariable struct {
+	key   string
+	value string
+}
+
+// An exported DVM-BASIC function, only those can be used as an entrypoint
+type ContractFunction struct {
+	name   string
+	params []ContractParam
+}
+
+type ContractParam struct {
+	name string
+	kind derorpc.DataType
+}
+
+// SIGNER() is only known to the contract at ring size 2
+const SC_RINGSIZE = 2
+
+var scFunction = regexp.MustCompile(`(?im)^\s*Function\s+([A-Za-z_][A-Za-z0-9_]*)\s*\(([^)]*)\)`)
+
+func validSCID(scid string) error {
+	if b, err := hex.DecodeString(scid); err != nil || len(b) != 32 {
+		return fmt.Errorf("SCID must be 64 hex characters")
+	}
+
+	return nil
+}
+
+// Read code, variables and balances through the embedded daemon's GetSC
+func loadContract(scid string) (c Contract, err error) {
+	if bw.chain == nil {
+		return c, fmt.Errorf("daemon is not running")
+	}
+
+	scid = strings.ToLower(strings.TrimSpace(scid))
+	if err = validSCID(scid); err != nil {
+		return
+	}
+
+	result, err := rpc.GetSC(context.Background(), derorpc.GetSC_Params{SCID: scid, Code: true, Variables: true})
+	if err != nil {
+		return
+	}
+
+	if result.Code == "" {
+		return c, fmt.Errorf("no smart contract %s on the local chain", scid)
+	}
+
+	c.scid = scid
+	c.code = result.Code
+	c.balance = result.Balance
+	c.functions = contractFunctions(result.Code)
+
+	for token, amount := range result.Balances {
+		c.balances = append(c.balances, ContractBalance{token: token, amount: amount})
+	}
+	sort.Slice(c.balances, func(i, j int) bool {
+		return c.balances[i].token < c.balances[j].token
+	})
+
+	for k, v := range result.VariableStringKeys {
+		// The code is stored as variable C, it is shown as source instead
+		if k == "C" {
+			continue
+		}
+		c.variables = append(c.variables, ContractVariable{key: k, value: contractValue(v)})
+	}
+	for k, v := range result.VariableUint64Keys {
+		c.variables = append(c.variables, ContractVariable{key: strconv.FormatUint(k, 10), value: contractValue(v)})
+	}
+	sort.Slice(c.variables, func(i, j int) bool {
+		return c.variables[i].key < c.variables[j].key
+	})
+
+	return c, nil
+}
+
+// String values come back hex encoded, show them as text when they are printable
+func contractValue(v interface{}) string {
+	switch v := v.(type) {
+	case uint64:
+		return strconv.FormatUint(v, 10)
+	case string:
+		if b, err := hex.DecodeString(v); err == nil && printable(b) {
+			return string(b)
+		}
+		return v
+	}
+
+	return fmt.Sprint(v)
+}
+
+func printable(b []byte) bool {
+	for _, c := range b {
+		if c < 0x20 || c > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Exported functions declared in the source, in source order
+func contractFunctions(code string) (functions []ContractFunction) {
+	for _, m := range scFunction.FindAllStringSubmatch(code, -1) {
+		name := m[1]
+		if name[0] < 'A' || name[0] > 'Z' {
+			continue
+		}
+
+		f := ContractFunction{name: name}
+		for _, p := range strings.Split(m[2], ",") {
+			fields := strings.Fields(p)
+			if len(fields) != 2 {
+				continue
+			}
+
+			kind := derorpc.DataString
+			if strings.EqualFold(fields[1], "Uint64") {
+				kind = derorpc.DataUint64
+			}
+			f.params = append(f.params, ContractParam{name: fields[0], kind: kind})
+		}
+
+		functions = append(functions, f)
+	}
+
+	return
+}
+
+// Arguments of a call, values are given as text in the order of the function's parameters
+func invokeArguments(scid string, f ContractFunction, values []string) (args derorpc.Arguments, err error) {
+	if len(values) != len(f.params) {
+		return nil, fmt.Errorf("%s takes %d arguments", f.name, len(f.params))
+	}
+
+	args = derorpc.Arguments{
+		{Name: "entrypoint", DataType: derorpc.DataString, Value: f.name},
+		{Name: derorpc.SCACTION, DataType: derorpc.DataUint64, Value: uint64(derorpc.SC_CALL)},
+		{Name: derorpc.SCID, DataType: derorpc.DataHash, Value: crypto.HashHexToHash(scid)},
+	}
+
+	for i, p := range f.params {
+		switch p.kind {
+		case derorpc.DataUint64:
+			n, err := strconv.ParseUint(strings.TrimSpace(values[i]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%s must be a whole number", p.name)
+			}
+			args = append(args, derorpc.Argument{Name: p.name, DataType: derorpc.DataUint64, Value: n})
+		default:
+			args = append(args, derorpc.Argument{Name: p.name, DataType: derorpc.DataString, Value: values[i]})
+		}
+	}
+
+	return args, args.Validate_Arguments()
+}
+
+// Dry run the call as the dApp wallet that will send it, against the current state.
+// Nothing is written to the chain
+func estimateInvoke(args derorpc.Arguments, deposit uint64) (gas derorpc.GasEstimate_Result, signer string, err error) {
+	if bw.chain == nil {
+		return gas, "", fmt.Errorf("daemon is not running")
+	}
+
+	w := dappWallet.get()
+	if w == nil {
+		return gas, "", fmt.Errorf("no dApp wallet is open in Netrunner")
+	}
+	signer = w.GetAddress().String()
+
+	p := derorpc.GasEstimate_Params{SC_RPC: args, Signer: signer}
+	if deposit > 0 {
+		p.Transfers = []derorpc.Transfer{{Burn: deposit}}
+	}
+
+	gas, err = rpc.GetGasEstimate(context.Background(), p)
+
+	return gas, signer, err
+}
+
+// Build and send the call from the dApp wallet, as the wallet RPC scinvoke does. The wallet
+// must still be the signer of the dry run
+func sendInvoke(args derorpc.Arguments, deposit uint64, gas uint64, signer string) (txid string, err error) {
+	// Held until the transaction is sent, closing the wallet waits for it
+	w := dappWallet.acquire()
+	if w == nil {
+		return "", fmt.Errorf("no dApp wallet is open in Netrunner")
+	}
+	defer dappWallet.release()
+
+	if w.GetAddress().String() != signer {
+		return "", fmt.Errorf("dApp wallet changed since the dry run, estimate again")
+	}
+
+	if !w.GetMode() {
+		return "", fmt.Errorf("wallet is offline")
+	}
+
+	var transfers []derorpc.Transfer
+	if deposit > 0 {
+		random := w.Random_ring_members(crypto.ZEROHASH)
+		if len(random) < 3 {
+			return "", fmt.Errorf("could not get ring members")
+		}
+		transfers = append(transfers, derorpc.Transfer{Destination: random[0], Burn: deposit})
+	}
+
+	tx, err := w.TransferPayload0(transfers, SC_RINGSIZE, false, args, gas, false)
+	if err != nil {
+		return
+	}
+
+	if err = w.SendTransaction(tx); err != nil {
+		return
+	}
+
+	txid = tx.GetHash().String()
+	globals.Logger.Info("[Netrunner] Smart contract invoked", "scid", args.Value(derorpc.SCID, derorpc.DataHash), "entrypoint", args.Value("entrypoint", derorpc.DataString), "txid", txid)
+
+	return txid, nil
+}
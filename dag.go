@@ -0,0 +1,348 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+	"strings"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/widget"
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/cryptography/crypto"
+)
+
+// A topo block with the tips it references
+type DAGNode struct {
+	topo   int64
+	height int64
+	hash   crypto.Hash
+	tips   []crypto.Hash
+	side   bool
+	stable bool
+}
+
+// Everything the explorer shows of a block
+type BlockDetail struct {
+	hash       string
+	height     int64
+	topo       int64
+	timestamp  time.Time
+	difficulty uint64
+	tips       []string
+	miniblocks int
+	txs        []string
+	reward     uint64
+	side       bool
+	stable     bool
+	miner      string
+}
+
+const (
+	DAG_BLOCKS     = 50
+	DAG_COLUMN     = 56 // pixels between heights
+	DAG_ROW        = 30 // pixels between blocks at the same height
+	DAG_NODE_W     = 40
+	DAG_NODE_H     = 18
+	DAG_PAD        = 12
+	DAG_BLOCKS_MAX = 500
+)
+
+var dagWindows = []string{"20", "50", "100", "200"}
+
+// The last n topo blocks, oldest first
+func loadDAG(chain *blockchain.Blockchain, n int64) (nodes []DAGNode) {
+	if chain == nil {
+		return
+	}
+
+	top := chain.Load_TOPO_HEIGHT()
+	stable := chain.Get_Stable_Height()
+
+	start := top - n + 1
+	if start < 0 {
+		start = 0
+	}
+
+	for topo := start; topo <= top; topo++ {
+		record, err := chain.Store.Topo_store.Read(topo)
+		if err != nil || record.BLOCK_ID == [32]byte{} {
+			continue
+		}
+
+		bl, err := chain.Load_BL_FROM_ID(record.BLOCK_ID)
+		if err != nil {
+			continue
+		}
+
+		nodes = append(nodes, DAGNode{
+			topo:   topo,
+			height: record.Height,
+			hash:   record.BLOCK_ID,
+			tips:   bl.Tips,
+			side:   isSideBlock(chain, topo, record.Height),
+			stable: record.Height <= stable,
+		})
+	}
+
+	return
+}
+
+// Look a block up by hash or topo height
+func decodeBlock(ref string) (d BlockDetail, err error) {
+	chain := bw.chain
+	if chain == nil {
+		return d, fmt.Errorf("daemon is not running")
+	}
+
+	ref = strings.ToLower(strings.TrimSpace(ref))
+
+	var hash crypto.Hash
+	if topo, err := strconv.ParseInt(ref, 10, 64); err == nil {
+		record, err := chain.Store.Topo_store.Read(topo)
+		if err != nil || record.BLOCK_ID == [32]byte{} {
+			return d, fmt.Errorf("no block at topo height %d", topo)
+		}
+		hash = record.BLOCK_ID
+	} else if len(ref) == 64 {
+		hash = crypto.HashHexToHash(ref)
+	} else {
+		return d, fmt.Errorf("enter a block hash or topo height")
+	}
+
+	bl, err := chain.Load_BL_FROM_ID(hash)
+	if err != nil {
+		return d, fmt.Errorf("block %s is not known to the local node", hash)
+	}
+
+	d.hash = hash.String()
+	d.height = int64(bl.Height)
+	d.topo = -1
+	d.timestamp = time.UnixMilli(int64(bl.Timestamp))
+	d.miniblocks = len(bl.MiniBlocks)
+	d.stable = d.height <= chain.Get_Stable_Height()
+
+	for _, tip := range bl.Tips {
+		d.tips = append(d.tips, tip.String())
+	}
+
+	for _, tx := range bl.Tx_hashes {
+		d.txs = append(d.txs, tx.String())
+	}
+
+	if diff, err := chain.Store.Block_tx_store.ReadBlockDifficulty(hash); err == nil {
+		d.difficulty = diff.Uint64()
+	}
+
+	if chain.Is_Block_Topological_order(hash) {
+		d.topo = chain.Load_Block_Topological_order(hash)
+		if s, ok := loadBlockSample(chain, d.topo); ok {
+			d.side = s.side
+			d.reward = s.reward
+		}
+	}
+
+	if addr, err := integratorAddress(bl.Miner_TX.MinerAddress); err == nil {
+		d.miner = addr
+	}
+
+	return d, nil
+}
+
+// Blocks drawn in columns by height with lines to their tips, the stable region is shaded
+type DAG struct {
+	widget.BaseWidget
+	nodes    []DAGNode
+	OnTapped func(DAGNode)
+}
+
+type dagRenderer struct {
+	dag        *DAG
+	background *canvas.Rectangle
+	stable     *canvas.Rectangle
+	label      *canvas.Text
+	links      []*canvas.Line
+	boxes      []*canvas.Rectangle
+	objects    []fyne.CanvasObject
+}
+
+func NewDAG() *DAG {
+	d := &DAG{}
+	d.ExtendBaseWidget(d)
+
+	return d
+}
+
+func (d *DAG) SetNodes(nodes []DAGNode) {
+	d.nodes = nodes
+	d.Refresh()
+}
+
+// Column and row of every node, blocks at the same height share a column
+func (d *DAG) positions() (pos map[crypto.Hash]fyne.Position, columns int, rows int) {
+	pos = map[crypto.Hash]fyne.Position{}
+	if len(d.nodes) == 0 {
+		return
+	}
+
+	base := d.nodes[0].height
+	used := map[int64]int{}
+	for _, n := range d.nodes {
+		column := n.height - base
+		row := used[column]
+		used[column]++
+
+		pos[n.hash] = fyne.NewPos(DAG_PAD+float32(column)*DAG_COLUMN, DAG_PAD+float32(row)*DAG_ROW)
+
+		if int(column)+1 > columns {
+			columns = int(column) + 1
+		}
+		if row+1 > rows {
+			rows = row + 1
+		}
+	}
+
+	return
+}
+
+func (d *DAG) Tapped(ev *fyne.PointEvent) {
+	if d.OnTapped == nil {
+		return
+	}
+
+	pos, _, _ := d.positions()
+	for _, n := range d.nodes {
+		p := pos[n.hash]
+		if ev.Position.X >= p.X && ev.Position.X <= p.X+DAG_NODE_W && ev.Position.Y >= p.Y && ev.Position.Y <= p.Y+DAG_NODE_H {
+			d.OnTapped(n)
+			return
+		}
+	}
+}
+
+func (d *DAG) CreateRenderer() fyne.WidgetRenderer {
+	r := &dagRenderer{
+		dag:        d,
+		background: canvas.NewRectangle(colors.darkmatter),
+		stable:     canvas.NewRectangle(color.RGBA{55, 55, 55, 90}),
+		label:      canvas.NewText("STABLE", colors.gray),
+	}
+	r.label.TextSize = 10
+	r.label.TextStyle = fyne.TextStyle{Bold: true}
+	r.rebuild()
+
+	return r
+}
+
+func (r *dagRenderer) rebuild() {
+	links := 0
+	for _, n := range r.dag.nodes {
+		links += len(n.tips)
+	}
+
+	for len(r.links) < links {
+		l := canvas.NewLine(colors.gray)
+		l.StrokeWidth = 1
+		r.links = append(r.links, l)
+	}
+	r.links = r.links[:links]
+
+	for len(r.boxes) < len(r.dag.nodes) {
+		r.boxes = append(r.boxes, canvas.NewRectangle(colors.red))
+	}
+	r.boxes = r.boxes[:len(r.dag.nodes)]
+
+	r.objects = []fyne.CanvasObject{r.background, r.stable, r.label}
+	for _, l := range r.links {
+		r.objects = append(r.objects, l)
+	}
+	for _, b := range r.boxes {
+		r.objects = append(r.objects, b)
+	}
+}
+
+func (r *dagRenderer) Layout(size fyne.Size) {
+	r.background.Resize(size)
+
+	pos, _, _ := r.dag.positions()
+
+	// Shade up to the last stable column
+	stable := float32(0)
+	for _, n := range r.dag.nodes {
+		if n.stable {
+			if x := pos[n.hash].X + DAG_NODE_W + (DAG_COLUMN-DAG_NODE_W)/2; x > stable {
+				stable = x
+			}
+		}
+	}
+	r.stable.Move(fyne.NewPos(0, 0))
+	r.stable.Resize(fyne.NewSize(stable, size.Height))
+	r.label.Move(fyne.NewPos(DAG_PAD, size.Height-DAG_PAD-10))
+	r.label.Hidden = stable == 0
+
+	i := 0
+	for _, n := range r.dag.nodes {
+		from := pos[n.hash]
+		for _, tip := range n.tips {
+			l := r.links[i]
+			i++
+
+			// Tips older than the window run off the left edge
+			to, ok := pos[tip]
+			if !ok {
+				to = fyne.NewPos(-DAG_NODE_W, from.Y)
+			}
+			l.Position1 = fyne.NewPos(from.X, from.Y+DAG_NODE_H/2)
+			l.Position2 = fyne.NewPos(to.X+DAG_NODE_W, to.Y+DAG_NODE_H/2)
+		}
+	}
+
+	for j, n := range r.dag.nodes {
+		b := r.boxes[j]
+		b.FillColor = colors.red
+		if n.side {
+			b.FillColor = colors.yellow
+		}
+		b.Move(pos[n.hash])
+		b.Resize(fyne.NewSize(DAG_NODE_W, DAG_NODE_H))
+	}
+}
+
+func (r *dagRenderer) MinSize() fyne.Size {
+	_, columns, rows := r.dag.positions()
+	if rows < 2 {
+		rows = 2
+	}
+
+	return fyne.NewSize(2*DAG_PAD+float32(columns)*DAG_COLUMN, 2*DAG_PAD+float32(rows)*DAG_ROW+10)
+}
+
+func (r *dagRenderer) Refresh() {
+	r.rebuild()
+	r.Layout(r.dag.Size())
+	for _, o := range r.objects {
+		o.Refresh()
+	}
+}
+
+func (r *dagRenderer) Objects() []fyne.CanvasObject {
+	return r.objects
+}
+
+func (r *dagRenderer) Destroy() {}
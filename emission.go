@@ -0,0 +1,193 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"sync"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/config"
+)
+
+// Rewards paid out by the local chain. Every topo block earns the block reward
+// of its height, so side blocks make the real supply run ahead of the schedule
+type Emission struct {
+	sync.Mutex
+	network   string
+	base      uint64 // rewards of topo blocks 1 to base_topo, deep enough not to change
+	base_topo int64
+	emitted   uint64 // rewards up to topo
+	topo      int64
+	missing   int64 // topo records not held locally, fast synced or pruned
+	synced    bool
+}
+
+// Emission figures at a height, derived from the reward schedule in blockchain.CalcBlockReward
+type EmissionStats struct {
+	height       uint64
+	supply       uint64 // premine plus rewards counted from the local chain, or the schedule until counted
+	scheduled    uint64 // premine plus one block reward per height
+	exact        bool
+	reward       uint64
+	share        uint64 // paid to each miniblock
+	integrator   uint64 // share plus the rounding leftover, paid to the final miniblock
+	per_day      uint64
+	per_year     uint64
+	inflation    float64 // percent a year at the current supply
+	next_halving uint64
+}
+
+const (
+	MINIBLOCKS_PER_BLOCK    = config.BLOCK_TIME - config.MINIBLOCK_HIGHDIFF + 1
+	BLOCKS_PER_DAY          = 24 * 60 * 60 / config.BLOCK_TIME
+	BLOCKS_PER_YEAR         = 365 * BLOCKS_PER_DAY
+	EMISSION_REORG_DEPTH    = 20
+	EMISSION_BATCH          = 100000
+	REWARD_REDUCTION_PERIOD = uint64(blockchain.RewardReductionInterval)
+)
+
+var emission Emission
+
+// Rewards of heights 1 to height with one block each, genesis carries the premine only.
+// The reward halves every REWARD_REDUCTION_PERIOD heights, so sum era by era
+func emittedTo(height uint64) (total uint64) {
+	for start := uint64(1); start <= height; {
+		end := (start/REWARD_REDUCTION_PERIOD + 1) * REWARD_REDUCTION_PERIOD
+		if end > height+1 {
+			end = height + 1
+		}
+
+		reward := blockchain.CalcBlockReward(start)
+		if reward == 0 {
+			break
+		}
+
+		total += reward * (end - start)
+		start = end
+	}
+
+	return
+}
+
+// Supply by the schedule alone
+func scheduledSupply(height uint64) uint64 {
+	return config.PREMINE + emittedTo(height)
+}
+
+// Split of a block reward, the integrator gets its own share plus what does not divide evenly
+func miniblockRewards(reward uint64) (share uint64, integrator uint64) {
+	share = reward / MINIBLOCKS_PER_BLOCK
+	integrator = reward - share*(MINIBLOCKS_PER_BLOCK-1)
+
+	return
+}
+
+// Rewards of the year after height, a halving inside the year is accounted for
+func yearlyEmission(height uint64) uint64 {
+	return emittedTo(height+BLOCKS_PER_YEAR) - emittedTo(height)
+}
+
+// Percent added to supply over the next year
+func inflation(height uint64, supply uint64) float64 {
+	if supply == 0 {
+		return 0
+	}
+
+	return float64(yearlyEmission(height)) / float64(supply) * 100
+}
+
+// First height paying a reduced reward
+func nextHalving(height uint64) uint64 {
+	return (height/REWARD_REDUCTION_PERIOD + 1) * REWARD_REDUCTION_PERIOD
+}
+
+func emissionStats(height uint64, counted uint64, exact bool) (s EmissionStats) {
+	s.height = height
+	s.scheduled = scheduledSupply(height)
+	s.supply = s.scheduled
+	if exact {
+		s.supply = counted
+		s.exact = true
+	}
+
+	s.reward = blockchain.CalcBlockReward(height)
+	s.share, s.integrator = miniblockRewards(s.reward)
+	s.per_day = s.reward * BLOCKS_PER_DAY
+	s.per_year = yearlyEmission(height)
+	s.inflation = inflation(height, s.supply)
+	s.next_halving = nextHalving(height)
+
+	return
+}
+
+// Count the rewards of new topo blocks, a long chain is caught up over several calls
+func (e *Emission) update(chain *blockchain.Blockchain) {
+	e.Lock()
+	defer e.Unlock()
+
+	top := chain.Load_TOPO_HEIGHT()
+
+	if e.network != networkName() || top < e.base_topo {
+		e.network = networkName()
+		e.base, e.base_topo, e.emitted, e.topo, e.missing, e.synced = 0, 0, 0, 0, 0, false
+	}
+
+	// Blocks this deep are not reordered any more, fold them into the base
+	stable := top - EMISSION_REORG_DEPTH
+	for n := 0; e.base_topo < stable && n < EMISSION_BATCH; n++ {
+		reward, ok := topoReward(chain, e.base_topo+1)
+		if !ok {
+			e.missing++
+		}
+		e.base += reward
+		e.base_topo++
+	}
+
+	if e.base_topo < stable {
+		e.synced = false
+		return
+	}
+
+	emitted := e.base
+	for t := e.base_topo + 1; t <= top; t++ {
+		reward, _ := topoReward(chain, t)
+		emitted += reward
+	}
+
+	e.emitted = emitted
+	e.topo = top
+	e.synced = true
+}
+
+func topoReward(chain *blockchain.Blockchain, topo int64) (uint64, bool) {
+	record, err := chain.Store.Topo_store.Read(topo)
+	if err != nil || record.BLOCK_ID == [32]byte{} {
+		return 0, false
+	}
+
+	return blockchain.CalcBlockReward(uint64(record.Height)), true
+}
+
+// Stats at height, the counted supply is only used once every topo block was seen
+func (e *Emission) stats(height int64) EmissionStats {
+	e.Lock()
+	defer e.Unlock()
+
+	if height < 0 {
+		height = 0
+	}
+
+	return emissionStats(uint64(height), config.PREMINE+e.emitted, e.synced && e.missing == 0)
+}
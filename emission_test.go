@@ -0,0 +1,121 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/config"
+)
+
+var emissionHeights = []uint64{
+	0,
+	1,
+	REWARD_REDUCTION_PERIOD - 1,
+	REWARD_REDUCTION_PERIOD,
+	2*REWARD_REDUCTION_PERIOD + 12345,
+}
+
+// Rewards of heights from+1 to to summed one block at a time
+func sumRewards(from uint64, to uint64) (total uint64) {
+	for h := from + 1; h <= to; h++ {
+		total += blockchain.CalcBlockReward(h)
+	}
+
+	return
+}
+
+func TestEmittedTo(t *testing.T) {
+	for _, h := range emissionHeights {
+		if got, want := emittedTo(h), sumRewards(0, h); got != want {
+			t.Errorf("emittedTo(%d) = %d, want %d", h, got, want)
+		}
+	}
+}
+
+func TestScheduledSupply(t *testing.T) {
+	for _, h := range emissionHeights {
+		if got, want := scheduledSupply(h), config.PREMINE+sumRewards(0, h); got != want {
+			t.Errorf("scheduledSupply(%d) = %d, want %d", h, got, want)
+		}
+	}
+}
+
+// Mainnet supply worked out by hand: the 12281254 DERO premine plus 0.615 DERO a block,
+// halved to 0.3075 from height 7000000
+func TestMainnetSupply(t *testing.T) {
+	want := []struct {
+		height uint64
+		supply uint64
+	}{
+		{21479, 1229446358500},   // last height before the first hardfork
+		{6999999, 1658625338500}, // last height before the first halving
+		{7000000, 1658625369250}, // first height of the halved reward
+		{4000000, 1474125400000}, // a current height
+	}
+
+	for _, w := range want {
+		if got := scheduledSupply(w.height); got != w.supply {
+			t.Errorf("scheduledSupply(%d) = %d, want %d", w.height, got, w.supply)
+		}
+	}
+}
+
+func TestMiniblockRewards(t *testing.T) {
+	for _, h := range emissionHeights {
+		reward := blockchain.CalcBlockReward(h)
+		share, integrator := miniblockRewards(reward)
+
+		if share*(MINIBLOCKS_PER_BLOCK-1)+integrator != reward {
+			t.Errorf("height %d: %d miniblocks of %d plus %d do not add up to %d", h, MINIBLOCKS_PER_BLOCK-1, share, integrator, reward)
+		}
+
+		if integrator < share || integrator-share >= MINIBLOCKS_PER_BLOCK {
+			t.Errorf("height %d: integrator %d is not the share %d plus the remainder", h, integrator, share)
+		}
+	}
+}
+
+func TestYearlyEmission(t *testing.T) {
+	for _, h := range emissionHeights {
+		if got, want := yearlyEmission(h), sumRewards(h, h+BLOCKS_PER_YEAR); got != want {
+			t.Errorf("yearlyEmission(%d) = %d, want %d", h, got, want)
+		}
+	}
+}
+
+func TestNextHalving(t *testing.T) {
+	want := map[uint64]uint64{
+		0:                                 REWARD_REDUCTION_PERIOD,
+		1:                                 REWARD_REDUCTION_PERIOD,
+		REWARD_REDUCTION_PERIOD - 1:       REWARD_REDUCTION_PERIOD,
+		REWARD_REDUCTION_PERIOD:           2 * REWARD_REDUCTION_PERIOD,
+		2*REWARD_REDUCTION_PERIOD + 12345: 3 * REWARD_REDUCTION_PERIOD,
+	}
+
+	for _, h := range emissionHeights {
+		next := nextHalving(h)
+		if next != want[h] {
+			t.Errorf("nextHalving(%d) = %d, want %d", h, next, want[h])
+		}
+
+		reward := blockchain.CalcBlockReward(h)
+		if blockchain.CalcBlockReward(next-1) != reward || blockchain.CalcBlockReward(next) >= reward {
+			t.Errorf("height %d: reward does not drop first at %d", h, next)
+		}
+	}
+}
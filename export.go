@@ -0,0 +1,415 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/cryptography/crypto"
+	"github.com/deroproject/derohe/globals"
+	"github.com/deroproject/derohe/transaction"
+)
+
+// Chain range exports stream one row per block, miniblock or transaction included in a block,
+// columns are only ever appended so existing readers keep working
+const (
+	EXPORT_BLOCKS     = "blocks"
+	EXPORT_MINIBLOCKS = "miniblocks"
+	EXPORT_TXS        = "txs"
+
+	EXPORT_CSV    = "csv"
+	EXPORT_NDJSON = "ndjson"
+)
+
+var exportKinds = []string{EXPORT_BLOCKS, EXPORT_MINIBLOCKS, EXPORT_TXS}
+var exportFormats = []string{EXPORT_CSV, EXPORT_NDJSON}
+
+type ExportOptions struct {
+	kind   string
+	format string
+	from   int64
+	to     int64 // -1 is the top of the chain
+}
+
+// Timestamps are milliseconds, amounts are atomic units
+type ExportBlock struct {
+	Height     int64  `json:"height"`
+	Topo       int64  `json:"topo"`
+	Hash       string `json:"hash"`
+	Timestamp  uint64 `json:"timestamp"`
+	Difficulty uint64 `json:"difficulty"`
+	Miniblocks int    `json:"miniblocks"`
+	Txs        int    `json:"txs"`
+	Side       bool   `json:"side"`
+	BaseReward uint64 `json:"base_reward"`
+	Fees       uint64 `json:"fees"`
+}
+
+// Miniblocks only carry a rolling 16 bit time and the first 16 bytes of the miner key hash
+type ExportMiniblock struct {
+	Height      int64  `json:"height"`
+	Topo        int64  `json:"topo"`
+	Block       string `json:"block"`
+	Index       int    `json:"index"`
+	Final       bool   `json:"final"`
+	HighDiff    bool   `json:"high_diff"`
+	KeyHash     string `json:"key_hash"`
+	RollingTime uint16 `json:"rolling_time"`
+	PastCount   uint8  `json:"past_count"`
+}
+
+// A transaction can be included by more than one block of the DAG, each inclusion is a row
+type ExportTx struct {
+	Height   int64  `json:"height"`
+	Topo     int64  `json:"topo"`
+	Block    string `json:"block"`
+	TxID     string `json:"txid"`
+	Type     string `json:"type"`
+	Fees     uint64 `json:"fees"`
+	Size     int    `json:"size"`
+	Payloads int    `json:"payloads"`
+	RingSize uint64 `json:"ring_size"`
+	Burn     uint64 `json:"burn"`
+}
+
+var exportColumns = map[string][]string{
+	EXPORT_BLOCKS:     {"height", "topo", "hash", "timestamp", "difficulty", "miniblocks", "txs", "side", "base_reward", "fees"},
+	EXPORT_MINIBLOCKS: {"height", "topo", "block", "index", "final", "high_diff", "key_hash", "rolling_time", "past_count"},
+	EXPORT_TXS:        {"height", "topo", "block", "txid", "type", "fees", "size", "payloads", "ring_size", "burn"},
+}
+
+type exportRow interface {
+	record() []string
+}
+
+func (b ExportBlock) record() []string {
+	return []string{strconv.FormatInt(b.Height, 10), strconv.FormatInt(b.Topo, 10), b.Hash, strconv.FormatUint(b.Timestamp, 10), strconv.FormatUint(b.Difficulty, 10),
+		strconv.Itoa(b.Miniblocks), strconv.Itoa(b.Txs), strconv.FormatBool(b.Side), strconv.FormatUint(b.BaseReward, 10), strconv.FormatUint(b.Fees, 10)}
+}
+
+func (m ExportMiniblock) record() []string {
+	return []string{strconv.FormatInt(m.Height, 10), strconv.FormatInt(m.Topo, 10), m.Block, strconv.Itoa(m.Index), strconv.FormatBool(m.Final),
+		strconv.FormatBool(m.HighDiff), m.KeyHash, strconv.FormatUint(uint64(m.RollingTime), 10), strconv.FormatUint(uint64(m.PastCount), 10)}
+}
+
+func (t ExportTx) record() []string {
+	return []string{strconv.FormatInt(t.Height, 10), strconv.FormatInt(t.Topo, 10), t.Block, t.TxID, t.Type, strconv.FormatUint(t.Fees, 10),
+		strconv.Itoa(t.Size), strconv.Itoa(t.Payloads), strconv.FormatUint(t.RingSize, 10), strconv.FormatUint(t.Burn, 10)}
+}
+
+func (opts ExportOptions) validate() error {
+	if _, ok := exportColumns[opts.kind]; !ok {
+		return fmt.Errorf("unknown export kind %q", opts.kind)
+	}
+
+	if opts.format != EXPORT_CSV && opts.format != EXPORT_NDJSON {
+		return fmt.Errorf("unknown export format %q", opts.format)
+	}
+
+	if opts.to >= 0 && opts.to < opts.from {
+		return fmt.Errorf("height range %d-%d is empty", opts.from, opts.to)
+	}
+
+	return nil
+}
+
+// Rows are written as they are read, nothing of the range is held in memory
+type exportWriter struct {
+	csv  *csv.Writer
+	json *json.Encoder
+}
+
+func newExportWriter(w io.Writer, opts ExportOptions) (*exportWriter, error) {
+	switch opts.format {
+	case EXPORT_CSV:
+		e := &exportWriter{csv: csv.NewWriter(w)}
+		return e, e.csv.Write(exportColumns[opts.kind])
+	case EXPORT_NDJSON:
+		return &exportWriter{json: json.NewEncoder(w)}, nil
+	}
+
+	return nil, fmt.Errorf("unknown export format %q", opts.format)
+}
+
+func (e *exportWriter) write(row exportRow) error {
+	if e.csv != nil {
+		return e.csv.Write(row.record())
+	}
+
+	return e.json.Encode(row)
+}
+
+func (e *exportWriter) flush() error {
+	if e.csv != nil {
+		e.csv.Flush()
+		return e.csv.Error()
+	}
+
+	return nil
+}
+
+// Format from a file name, csv unless it ends in .ndjson, .jsonl or .json
+func exportFormat(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".ndjson", ".jsonl", ".json":
+		return EXPORT_NDJSON
+	}
+
+	return EXPORT_CSV
+}
+
+func exportName(opts ExportOptions) string {
+	to := "top"
+	if opts.to >= 0 {
+		to = strconv.FormatInt(opts.to, 10)
+	}
+
+	return fmt.Sprintf("netrunner_%s_%d-%s.%s", opts.kind, opts.from, to, opts.format)
+}
+
+// First topo index at or above height, heights never decrease along the topo order
+func exportStart(chain *blockchain.Blockchain, height int64) int64 {
+	low, high := int64(0), chain.Store.Topo_store.Count()
+	for low < high {
+		mid := (low + high) / 2
+		record, err := chain.Store.Topo_store.Read(mid)
+		if err == nil && record.Height >= height {
+			high = mid
+		} else {
+			low = mid + 1
+		}
+	}
+
+	return low
+}
+
+// Stream the blocks from height opts.from to opts.to in topo order, pruned blocks are skipped
+func exportChain(chain *blockchain.Blockchain, w io.Writer, opts ExportOptions, progress func(float64)) (rows int64, skipped int64, err error) {
+	if err = opts.validate(); err != nil {
+		return
+	}
+
+	out, err := newExportWriter(w, opts)
+	if err != nil {
+		return
+	}
+
+	start := exportStart(chain, opts.from)
+	end := chain.Store.Topo_store.Count()
+
+	for topo := start; topo < end; topo++ {
+		record, err := chain.Store.Topo_store.Read(topo)
+		if err != nil {
+			return rows, skipped, err
+		}
+
+		if opts.to >= 0 && record.Height > opts.to {
+			break
+		}
+
+		if topo%1000 == 0 && progress != nil {
+			progress(float64(topo-start) / float64(end-start))
+		}
+
+		bl, err := chain.Load_BL_FROM_ID(record.BLOCK_ID)
+		if record.BLOCK_ID == [32]byte{} || err != nil {
+			skipped++
+			continue
+		}
+
+		hash := crypto.Hash(record.BLOCK_ID).String()
+
+		switch opts.kind {
+		case EXPORT_BLOCKS:
+			row := ExportBlock{
+				Height:     record.Height,
+				Topo:       topo,
+				Hash:       hash,
+				Timestamp:  bl.Timestamp,
+				Miniblocks: len(bl.MiniBlocks),
+				Txs:        len(bl.Tx_hashes),
+				Side:       isSideBlock(chain, topo, record.Height),
+				BaseReward: blockchain.CalcBlockReward(uint64(record.Height)),
+			}
+
+			if diff, err := chain.Store.Block_tx_store.ReadBlockDifficulty(record.BLOCK_ID); err == nil {
+				row.Difficulty = diff.Uint64()
+			}
+
+			for _, txid := range bl.Tx_hashes {
+				if data, err := chain.Store.Block_tx_store.ReadTX(txid); err == nil {
+					var tx transaction.Transaction
+					if tx.Deserialize(data) == nil {
+						row.Fees += tx.Fees()
+					}
+				}
+			}
+
+			if err = out.write(row); err != nil {
+				return rows, skipped, err
+			}
+			rows++
+
+		case EXPORT_MINIBLOCKS:
+			for i, mbl := range bl.MiniBlocks {
+				row := ExportMiniblock{
+					Height:      record.Height,
+					Topo:        topo,
+					Block:       hash,
+					Index:       i,
+					Final:       mbl.Final,
+					HighDiff:    mbl.HighDiff,
+					KeyHash:     hex.EncodeToString(mbl.KeyHash[:16]),
+					RollingTime: mbl.Timestamp,
+					PastCount:   mbl.PastCount,
+				}
+
+				if err = out.write(row); err != nil {
+					return rows, skipped, err
+				}
+				rows++
+			}
+
+		case EXPORT_TXS:
+			for _, txid := range bl.Tx_hashes {
+				row := ExportTx{
+					Height: record.Height,
+					Topo:   topo,
+					Block:  hash,
+					TxID:   txid.String(),
+				}
+
+				data, err := chain.Store.Block_tx_store.ReadTX(txid)
+				if err != nil {
+					skipped++
+					continue
+				}
+
+				var tx transaction.Transaction
+				if err = tx.Deserialize(data); err != nil {
+					skipped++
+					continue
+				}
+
+				row.Type = txKind(&tx)
+				row.Fees = tx.Fees()
+				row.Size = len(data)
+				row.Payloads = len(tx.Payloads)
+				for _, p := range tx.Payloads {
+					row.Burn += p.BurnValue
+					if p.Statement.RingSize > row.RingSize {
+						row.RingSize = p.Statement.RingSize
+					}
+				}
+
+				if err = out.write(row); err != nil {
+					return rows, skipped, err
+				}
+				rows++
+			}
+		}
+	}
+
+	if progress != nil {
+		progress(1)
+	}
+
+	return rows, skipped, out.flush()
+}
+
+// Export without starting the daemon, only the block and topo stores of the data directory are opened
+func exportCommand() int {
+	opts := ExportOptions{kind: EXPORT_BLOCKS, to: -1}
+	name := globals.Arguments["--export"].(string)
+
+	if kind, ok := globals.Arguments["--export-kind"].(string); ok {
+		opts.kind = strings.ToLower(kind)
+	}
+
+	opts.format = exportFormat(name)
+	if format, ok := globals.Arguments["--export-format"].(string); ok {
+		opts.format = strings.ToLower(format)
+	}
+
+	for flag, v := range map[string]*int64{"--export-from": &opts.from, "--export-to": &opts.to} {
+		if s, ok := globals.Arguments[flag].(string); ok {
+			n, err := strconv.ParseInt(s, 10, 64)
+			if err != nil || n < 0 {
+				fmt.Fprintf(os.Stderr, "%s: invalid height %q\n", flag, s)
+				return 2
+			}
+			*v = n
+		}
+	}
+
+	// Nothing is opened or truncated for options that cannot run
+	if err := opts.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 2
+	}
+
+	if err := loadConfig(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	initGlobals()
+
+	dir := globals.GetDataDirectory()
+	if err := acquireLock(dir); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		return 1
+	}
+	defer releaseLock()
+
+	var chain blockchain.Blockchain
+	if err := chain.Store.Initialize(nil); err != nil {
+		fmt.Fprintf(os.Stderr, "cannot open the chain at %s: %s\n", dir, err)
+		return 1
+	}
+	defer chain.Store.Balance_store.Close()
+
+	w := io.Writer(os.Stdout)
+	if name != "-" {
+		file, err := os.Create(name)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			return 1
+		}
+		defer file.Close()
+		w = file
+	}
+
+	rows, skipped, err := exportChain(&chain, w, opts, func(f float64) {
+		fmt.Fprintf(os.Stderr, "\rExporting %s... %3.0f%%", opts.kind, f*100)
+	})
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "export failed after %d rows: %s\n", rows, err)
+		return 1
+	}
+
+	fmt.Fprintf(os.Stderr, "Exported %d %s rows from %s (%d pruned or unreadable skipped)\n", rows, opts.kind, dir, skipped)
+
+	return 0
+}
@@ -18,7 +18,6 @@ package main
 import (
 	"fmt"
 	"net"
-	"os"
 	"runtime"
 	"time"
 
@@ -27,7 +26,6 @@ import (
 	"github.com/deroproject/derohe/blockchain"
 	"github.com/deroproject/derohe/cmd/derod/rpc"
 	"github.com/deroproject/derohe/config"
-	"github.com/deroproject/derohe/cryptography/crypto"
 	"github.com/deroproject/derohe/globals"
 	"github.com/deroproject/derohe/p2p"
 )
@@ -35,7 +33,10 @@ import (
 type Blackwall struct {
 	chain  *blockchain.Blockchain
 	server *rpc.RPCServer
+	guard  *RPCGuard
+	rpc    string // address derod RPC listens on, behind the guard when there is one
 	status int64
+	ran    bool // derod ran in this process, its getwork server and P2P goroutines outlive a stop
 }
 
 type resources struct {
@@ -58,10 +59,14 @@ const (
 )
 
 var res resources
+
+// Start the daemon and toggle the miner the same way as their RUN buttons, set by layoutMain
+var runDaemon func()
+var runMiner func()
 var command_line string = `derod 
 DERO : A secure, private blockchain with smart-contracts
 Usage:
-  derod [--version] [--testnet] [--debug]  [--sync-node] [--timeisinsync] [--fastsync] [--socks-proxy=<socks_ip:port>] [--data-dir=<directory>] [--p2p-bind=<0.0.0.0:18089>] [--add-exclusive-node=<ip:port>]... [--add-priority-node=<ip:port>]... [--min-peers=<11>] [--max-peers=<100>] [--rpc-bind=<127.0.0.1:9999>] [--getwork-bind=<0.0.0.0:18089>] [--node-tag=<unique name>] [--prune-history=<50>] [--integrator-address=<address>] [--clog-level=1] [--flog-level=1]
+  derod [--version] [--testnet] [--debug]  [--sync-node] [--timeisinsync] [--fastsync] [--socks-proxy=<socks_ip:port>] [--data-dir=<directory>] [--p2p-bind=<0.0.0.0:18089>] [--add-exclusive-node=<ip:port>]... [--add-priority-node=<ip:port>]... [--min-peers=<11>] [--max-peers=<100>] [--rpc-bind=<127.0.0.1:9999>] [--getwork-bind=<0.0.0.0:18089>] [--node-tag=<unique name>] [--prune-history=<50>] [--integrator-address=<address>] [--clog-level=1] [--flog-level=1] [--export=<file>] [--export-kind=<blocks>] [--export-format=<csv>] [--export-from=<height>] [--export-to=<height>]
   derod --version
 Options:
   --version     Show version.
@@ -84,6 +89,11 @@ Options:
   --min-peers=<31>	  Node will try to maintain atleast this many connections to peers
   --max-peers=<101>	  Node will maintain maximim this many connections to peers and will stop accepting connections
   --prune-history=<50>	prunes blockchain history until the specific topo_height
+  --export=<file>	Export a height range of the local chain to file (- for stdout) and exit, the daemon is not started
+  --export-kind=<blocks>	Rows to export, blocks, miniblocks or txs
+  --export-format=<csv>	csv or ndjson, taken from the file extension when not given
+  --export-from=<height>	First height to export, 0 when not given
+  --export-to=<height>	Last height to export, the top of the chain when not given
   `
 
 // Load the resources as images from bundled.go
@@ -102,11 +112,33 @@ func loadResources() {
 
 func startDaemon() {
 	if bw.chain == nil {
-		// Initialize DERO blockchain
-		bw.chain = derodpkg.InitializeDerod(globals.Arguments)
+		if status.simulator {
+			var err error
+			if bw.chain, bw.server, err = startSimulator(); err != nil {
+				globals.Logger.Error(err, "[Netrunner] Simulator failed to start")
+				releaseLock()
+				return
+			}
+			followDaemonLog()
+			openMetrics(status.metrics_disk)
+		} else {
+			// Initialize DERO blockchain
+			bw.chain = derodpkg.InitializeDerod(globals.Arguments)
+			followDaemonLog()
+			openMetrics(status.metrics_disk)
 
-		// Initialize RPC server
-		bw.server = derodpkg.StartDerod(bw.chain)
+			// Initialize RPC server, it reads its bind once running so a wallet RPC
+			// must not swap it before then
+			argsLock.Lock()
+			bw.server = derodpkg.StartDerod(bw.chain)
+			if bind, ok := globals.Arguments["--rpc-bind"].(string); ok && !waitListening(bind) {
+				globals.Logger.Error(nil, "[Netrunner] Daemon RPC is not listening", "address", bind)
+			}
+			argsLock.Unlock()
+			bw.ran = true
+		}
+
+		bw.rpc, _ = argument("--rpc-bind").(string)
 
 		res.daemon.Resource = resourceDaemonOnPng
 		res.daemon.Refresh()
@@ -117,62 +149,98 @@ func startDaemon() {
 		globals.Logger.Info("Copyright 2020-2023 DERO Foundation. All rights reserved.")
 		globals.Logger.Info("OS", runtime.GOOS, "ARCH", runtime.GOARCH, "GOMAXPROCS", runtime.GOMAXPROCS(0))
 
+		connectOpenWallets()
+
 		// Run the update routine
 		go update()
+		go watchDisk()
 	}
 }
 
-// Always call this for a graceful close
-func appClose() {
-	closeMiner()
+func closeDaemon() {
+	stopGatewayBackend()
+	stopSimulator()
+	stopRPC()
+	stopChain()
+}
+
+// Stop the miner and daemon in order, blocks until the chain is closed
+func stopDaemon() {
+	stopMiner()
 	closeDaemon()
-	os.Exit(0)
+	globals.Logger.Info("[Netrunner] Daemon stopped")
 }
 
-func closeDaemon() {
+func stopRPC() {
+	if bw.guard != nil {
+		bw.guard.Stop()
+		bw.guard = nil
+	}
+
 	if bw.server != nil {
 		bw.server.RPCServer_Stop()
 		bw.server = nil
 	}
 
+	bw.rpc = ""
+}
+
+// Shutdown flushes the chain database, wait for every derod subsystem to report done
+func stopChain() {
 	if bw.chain != nil {
+		// The simulator never starts P2P
+		if !status.simulator {
+			p2p.P2P_Shutdown()
+		}
 		bw.chain.Shutdown()
 		bw.chain = nil
+		status.active = 0
+		closeMetrics()
+
+		for globals.Subsystem_Active > 0 {
+			time.Sleep(100 * time.Millisecond)
+		}
 	}
+
+	releaseLock()
 }
 
 func closeMiner() {
 	if m.Mission == 1 {
 		m.Mission = 0
 	}
+	m.Speed = 0
 }
 
-func getStatus() {
-	if bw.chain == nil {
-		return
+// Stop the miner and wait for its threads, closing the connection unblocks getwork
+func stopMiner() {
+	closeMiner()
+
+	connection_mutex.Lock()
+	if m.Connection != nil {
+		m.Connection.Close()
 	}
+	connection_mutex.Unlock()
 
-	var zerohash crypto.Hash
+	miners.Wait()
+}
 
-	blid, err := bw.chain.Load_Block_Topological_order_at_index(bw.chain.Get_Height())
-	blid50, err := bw.chain.Load_Block_Topological_order_at_index(bw.chain.Get_Height() - 50)
-	if err == nil {
-		if blid50 != zerohash {
-			now := bw.chain.Load_Block_Timestamp(blid)
-			now50 := bw.chain.Load_Block_Timestamp(blid50)
-			status.block_time = float32(now-now50) / (50.0 * 1000)
-		} else {
-			status.block_time = 0
-		}
-	} else {
-		status.block_time = 0
+func getStatus() {
+	// The daemon can be stopped while this runs, keep hold of the chain
+	chain := bw.chain
+	if chain == nil {
+		return
 	}
 
-	status.height = bw.chain.Get_Height()
-	status.difficulty = bw.chain.Get_Difficulty()
-	status.stable_height = bw.chain.Get_Stable_Height()
+	analytics.update(chain)
+	status.block_time = float32(analytics.stats(STATUS_WINDOW).mean)
+	status.net_hashrate = analytics.stats(HASHRATE_WINDOW).hashrate
+
+	status.height = chain.Get_Height()
+	status.difficulty = chain.Get_Difficulty()
+	status.stable_height = chain.Get_Stable_Height()
 	status.peers = p2p.Peer_Count()
-	status.peer_height, _ = p2p.Best_Peer_Height()
+	status.peer_height = networkHeight(chain)
 	status.miners = rpc.CountMiners()
 	status.estimate_1d = rpc.HashrateEstimatePercent_1day()
 	status.estimate_1hr = rpc.HashrateEstimatePercent_1hr()
@@ -180,9 +248,11 @@ func getStatus() {
 	status.blocks_accepted = rpc.CountMinisAccepted
 	status.blocks_rejected = rpc.CountMinisRejected
 	status.total_blocks = rpc.CountBlocks
-	status.supply = config.PREMINE + blockchain.CalcBlockReward(uint64(bw.chain.Get_Height()))*uint64(bw.chain.Get_Height())
-	status.tx_pool = len(bw.chain.Mempool.Mempool_List_TX())
-	status.reg_pool = len(bw.chain.Regpool.Regpool_List_TX())
+	emission.update(chain)
+	status.emission = emission.stats(status.height)
+	status.supply = status.emission.supply
+	status.tx_pool = len(chain.Mempool.Mempool_List_TX())
+	status.reg_pool = len(chain.Regpool.Regpool_List_TX())
 	status.uptime = time.Now().Sub(globals.StartTime).Round(time.Second).String()
 	status.version = config.Version.String()
 	status.offset_p2p = globals.GetOffsetP2P().Round(time.Millisecond).String()
@@ -191,12 +261,24 @@ func getStatus() {
 	return
 }
 
+// Height of the network as peers report it, the simulator has none and is always on top
+func networkHeight(chain *blockchain.Blockchain) int64 {
+	if status.simulator {
+		return chain.Get_Height()
+	}
+
+	height, _ := p2p.Best_Peer_Height()
+
+	return height
+}
+
 // Routine to update status per second
 func update() {
-	for bw.chain != nil {
+	for chain := bw.chain; chain != nil; chain = bw.chain {
 		getStatus()
+		recordMetrics()
 
-		status.last_height = bw.chain.Get_Height()
+		status.last_height = chain.Get_Height()
 
 		time.Sleep(1 * time.Second)
 	}
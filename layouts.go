@@ -18,6 +18,7 @@ package main
 import (
 	"fmt"
 	"image/color"
+	"os"
 	"runtime"
 	"strconv"
 	"strings"
@@ -26,10 +27,13 @@ import (
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/canvas"
 	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
 	"fyne.io/fyne/v2/layout"
+	"fyne.io/fyne/v2/theme"
 	"fyne.io/fyne/v2/widget"
+	"github.com/deroproject/derohe/blockchain"
 	"github.com/deroproject/derohe/globals"
-	"github.com/deroproject/derohe/p2p"
+	derorpc "github.com/deroproject/derohe/rpc"
 )
 
 type Colors struct {
@@ -41,15 +45,147 @@ type Colors struct {
 	white      color.RGBA
 }
 
+// Scrolls when the window is smaller than the layout, fyne takes the window minimum from its content
+func newWindowScroll(content fyne.CanvasObject) *container.Scroll {
+	scroll := container.NewScroll(content)
+	scroll.SetMinSize(fyne.NewSize(WINDOW_MIN_WIDTH, WINDOW_MIN_HEIGHT))
+
+	return scroll
+}
+
+// Columns sharing the width of their container by weight, so rows built with the same weights
+// line up. A column never gets less than its minimum, when the width runs short every column
+// gets its minimum and a weighted share of what is left
+type columnsLayout struct {
+	weights []float32
+}
+
+func newColumns(weights []float32, objects ...fyne.CanvasObject) *fyne.Container {
+	return container.New(&columnsLayout{weights: weights}, objects...)
+}
+
+func (c *columnsLayout) weight(i int) float32 {
+	if i < len(c.weights) {
+		return c.weights[i]
+	}
+
+	return 1
+}
+
+func (c *columnsLayout) MinSize(objects []fyne.CanvasObject) fyne.Size {
+	min := fyne.NewSize(0, 0)
+	for i, o := range objects {
+		size := o.MinSize()
+		if i > 0 {
+			min.Width += theme.Padding()
+		}
+		min.Width += size.Width
+		min.Height = fyne.Max(min.Height, size.Height)
+	}
+
+	return min
+}
+
+func (c *columnsLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+	if len(objects) == 0 {
+		return
+	}
+
+	width := size.Width - theme.Padding()*float32(len(objects)-1)
+	total, needed := float32(0), float32(0)
+	for i, o := range objects {
+		total += c.weight(i)
+		needed += o.MinSize().Width
+	}
+
+	widths := make([]float32, len(objects))
+	short := false
+	for i, o := range objects {
+		widths[i] = width * c.weight(i) / total
+		short = short || widths[i] < o.MinSize().Width
+	}
+
+	if short {
+		extra := fyne.Max(0, width-needed)
+		for i, o := range objects {
+			widths[i] = o.MinSize().Width + extra*c.weight(i)/total
+		}
+	}
+
+	x := float32(0)
+	for i, o := range objects {
+		o.Move(fyne.NewPos(x, 0))
+		o.Resize(fyne.NewSize(widths[i], size.Height))
+		x += widths[i] + theme.Padding()
+	}
+}
+
 func layoutLoad() fyne.CanvasObject {
 	loadResources()
 
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(MIN_WIDTH, MIN_HEIGHT))
+	btnRect := canvas.NewRectangle(color.Transparent)
+	btnRect.SetMinSize(fyne.NewSize(110, 30))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	loader.step = canvas.NewText("Starting...", colors.white)
+	loader.step.TextSize = 12
+	loader.step.TextStyle = fyne.TextStyle{Bold: true}
+	loader.step.Alignment = fyne.TextAlignCenter
+
+	loader.message = canvas.NewText("", colors.red)
+	loader.message.TextSize = 11
+	loader.message.Alignment = fyne.TextAlignCenter
+
+	loader.progress = widget.NewProgressBar()
+	loader.progress.TextFormatter = func() string {
+		return ""
+	}
+
+	loader.retry = widget.NewButton("RTY", nil)
+	loader.retry.Hide()
+
+	loader.quit = widget.NewButton("END", func() {
+		os.Exit(1)
+	})
+	loader.quit.Hide()
+
+	progress := container.NewVBox(
+		layout.NewSpacer(),
+		loader.step,
+		rectSpacer,
+		newColumns(
+			[]float32{1, 2, 1},
+			layout.NewSpacer(),
+			loader.progress,
+			layout.NewSpacer(),
+		),
+		rectSpacer,
+		loader.message,
+		rectSpacer,
+		container.NewHBox(
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				loader.retry,
+			),
+			rectSpacer,
+			container.NewMax(
+				btnRect,
+				loader.quit,
+			),
+			layout.NewSpacer(),
+		),
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+		rectSpacer,
+	)
 
 	c := container.NewMax(
-		rect,
 		res.load,
+		progress,
 	)
 
 	layout := container.NewMax(
@@ -64,15 +200,9 @@ func layoutMain() fyne.CanvasObject {
 	loadResources()
 
 	m.Threads = runtime.GOMAXPROCS(0) / 2
-
-	status.fastsync = true
-	globals.Arguments["--fastsync"] = true
-	status.network = false
-	globals.Arguments["--testnet"] = false
-	globals.Initialize()
-
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(580, 300))
+	if status.threads > 0 && status.threads <= runtime.GOMAXPROCS(0) {
+		m.Threads = status.threads
+	}
 
 	rect1 := canvas.NewRectangle(color.Transparent)
 	rect1.SetMinSize(fyne.NewSize(1, 1))
@@ -86,23 +216,14 @@ func layoutMain() fyne.CanvasObject {
 	rect50 := canvas.NewRectangle(color.Transparent)
 	rect50.SetMinSize(fyne.NewSize(0, 50))
 
-	rect280 := canvas.NewRectangle(color.Transparent)
-	rect280.SetMinSize(fyne.NewSize(300, 300))
-
 	div := canvas.NewRectangle(colors.red)
-	div.SetMinSize(fyne.NewSize(500, 2))
+	div.SetMinSize(fyne.NewSize(0, 2))
 
 	div2 := canvas.NewRectangle(colors.gray)
-	div2.SetMinSize(fyne.NewSize(500, 1))
+	div2.SetMinSize(fyne.NewSize(0, 1))
 
 	div3 := canvas.NewRectangle(colors.gray)
-	div3.SetMinSize(fyne.NewSize(500, 1))
-
-	frame := canvas.NewRectangle(color.Transparent)
-	frame.SetMinSize(fyne.NewSize(MIN_WIDTH-10, 100))
-
-	rectCell := canvas.NewRectangle(color.Transparent)
-	rectCell.SetMinSize(fyne.NewSize(150, 5))
+	div3.SetMinSize(fyne.NewSize(0, 1))
 
 	rectSpacer := canvas.NewRectangle(color.Transparent)
 	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
@@ -125,6 +246,16 @@ func layoutMain() fyne.CanvasObject {
 
 	btnConfig := widget.NewButton("CFG", nil)
 
+	btnLogs := widget.NewButton("LOG", nil)
+
+	btnCharts := widget.NewButton("CHT", nil)
+
+	btnStats := widget.NewButton("STA", nil)
+
+	btnWallet := widget.NewButton("WLT", nil)
+
+	btnContracts := widget.NewButton("DVM", nil)
+
 	btnExplorer := widget.NewButton("EXP", nil)
 	btnExplorer.OnTapped = func() {
 		a.explorer.SetContent(layoutExplorer())
@@ -163,7 +294,12 @@ func layoutMain() fyne.CanvasObject {
 	btnStartMiner := widget.NewButton("RUN", nil)
 	btnStartMiner.OnTapped = func() {
 		if m.Mission == 0 {
-			go startRunner(bw.chain.IntegratorAddress().String(), fmt.Sprintf("127.0.0.1:%d", globals.Config.GETWORK_Default_Port), m.Threads)
+			address, getwork := bw.chain.IntegratorAddress().String(), fmt.Sprintf("127.0.0.1:%d", globals.Config.GETWORK_Default_Port)
+			if status.simulator {
+				address, getwork = simulatorMiner(), simulatorGetwork()
+			}
+
+			go startRunner(address, getwork, m.Threads)
 			minerTitle.Text = "Running"
 			minerTitle.Color = colors.red
 			minerTitle.Refresh()
@@ -182,6 +318,20 @@ func layoutMain() fyne.CanvasObject {
 	daemonIP := canvas.NewText("---", colors.gray)
 	daemonIP.TextSize = 20
 
+	rpcBadgeText := canvas.NewText(" PUBLIC ", colors.yellow)
+	rpcBadgeText.TextSize = 9
+	rpcBadgeText.TextStyle = fyne.TextStyle{Bold: true}
+
+	rpcBadgeFrame := canvas.NewRectangle(color.Transparent)
+	rpcBadgeFrame.StrokeColor = colors.yellow
+	rpcBadgeFrame.StrokeWidth = 1
+
+	rpcBadge := container.NewMax(
+		rpcBadgeFrame,
+		rpcBadgeText,
+	)
+	rpcBadge.Hide()
+
 	versionLabel := canvas.NewText("VERSION", colors.gray)
 	versionLabel.TextSize = 11
 
@@ -195,7 +345,7 @@ func layoutMain() fyne.CanvasObject {
 
 	minerLabel := canvas.NewText("WORK", colors.gray)
 	minerLabel.TextSize = 11
-	minerIP := canvas.NewText(GetIP().String()+":10100", colors.gray)
+	minerIP := canvas.NewText("---", colors.gray)
 	minerIP.TextSize = 11
 
 	netLabel := canvas.NewText("NETWORK", colors.gray)
@@ -296,33 +446,44 @@ func layoutMain() fyne.CanvasObject {
 	hashrate := canvas.NewText("---", colors.gray)
 	hashrate.TextSize = 16
 
-	// Config Panel
-	rectLeft := canvas.NewRectangle(color.Transparent)
-	rectLeft.SetMinSize(fyne.NewSize(200, 200))
+	shareLabel := canvas.NewText("SHARE", colors.gray)
+	shareLabel.TextSize = 10
+	shareLabel.TextStyle = fyne.TextStyle{Bold: true}
 
-	rectMid := canvas.NewRectangle(color.Transparent)
-	rectMid.SetMinSize(fyne.NewSize(500, 200))
+	share := canvas.NewText("---", colors.gray)
+	share.TextSize = 16
 
+	shareETA := canvas.NewText("", colors.gray)
+	shareETA.TextSize = 10
+
+	// Config Panel
 	rectSlider := canvas.NewRectangle(colors.darkmatter)
-	rectSlider.SetMinSize(fyne.NewSize(500, 30))
+	rectSlider.SetMinSize(fyne.NewSize(0, 30))
 
 	radNetworkLabel := canvas.NewText("NETWORK", colors.red)
 	radNetworkLabel.TextSize = 10
 	radNetworkLabel.TextStyle = fyne.TextStyle{Bold: true}
 
-	radNetwork := widget.NewRadioGroup([]string{"Mainnet", "Testnet"}, nil)
+	radNetwork := widget.NewRadioGroup([]string{networkLabels[NETWORK_MAINNET], networkLabels[NETWORK_TESTNET], networkLabels[NETWORK_SIMULATOR]}, nil)
+	radNetwork.SetSelected(networkLabels[networkName()])
 
-	if _, ok := globals.Arguments["--testnet"]; ok && globals.Arguments["--testnet"] != nil {
-		if globals.Arguments["--testnet"].(bool) {
-			radNetwork.SetSelected("Testnet")
-			status.network = true
+	// derod cannot be started twice in one process, once it has run a switch relaunches Netrunner
+	networkNote := canvas.NewText("Switching network now relaunches Netrunner", colors.yellow)
+	networkNote.TextSize = 10
+	networkNote.Hide()
+
+	refreshNetworkNote := func() {
+		if bw.ran {
+			networkNote.Show()
 		} else {
-			radNetwork.SetSelected("Mainnet")
-			status.network = false
+			networkNote.Hide()
 		}
-	} else {
-		radNetwork.SetSelected("Mainnet")
-		status.network = false
+	}
+
+	btnResetSim := widget.NewButton("RST", nil)
+	simReset := container.NewHBox(btnResetSim)
+	if !status.simulator {
+		simReset.Hide()
 	}
 
 	radSyncLabel := canvas.NewText("SYNC  MODE", colors.red)
@@ -379,17 +540,508 @@ func layoutMain() fyne.CanvasObject {
 		}
 	}
 	reward.SetPlaceHolder("Enter a DERO Address")
+	if status.integrator != "" {
+		reward.SetText(status.integrator)
+		reward.Validate()
+	}
+
+	launchLabel := canvas.NewText("ON  LAUNCH", colors.red)
+	launchLabel.TextSize = 10
+	launchLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	launchNote := canvas.NewText("Mining starts once the daemon reaches the best peer height", colors.gray)
+	launchNote.TextSize = 10
+
+	checkAutoStart := widget.NewCheck("Start daemon", func(b bool) {
+		status.auto_start = b
+	})
+	checkAutoStart.SetChecked(status.auto_start)
+
+	checkAutoMine := widget.NewCheck("Start mining when synced", func(b bool) {
+		status.auto_mine = b
+		if b && status.integrator == "" {
+			launchNote.Text = "Set a mining address or mining will not start"
+			launchNote.Color = colors.yellow
+		} else {
+			launchNote.Text = "Mining starts once the daemon reaches the best peer height"
+			launchNote.Color = colors.gray
+		}
+		launchNote.Refresh()
+	})
+	checkAutoMine.SetChecked(status.auto_mine)
+
+	radCloseLabel := canvas.NewText("CLOSE  BUTTON", colors.red)
+	radCloseLabel.TextSize = 10
+	radCloseLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	radClose := widget.NewRadioGroup([]string{CLOSE_QUITS, CLOSE_TO_TRAY}, func(s string) {
+		status.close_action = s
+	})
+	radClose.Horizontal = true
+	if status.close_action == CLOSE_TO_TRAY && tray.active {
+		radClose.SetSelected(CLOSE_TO_TRAY)
+	} else {
+		radClose.SetSelected(CLOSE_QUITS)
+	}
+
+	// Without a tray there is nothing to minimize to
+	if !tray.active {
+		radClose.Disable()
+	}
+
+	// The desktop entry is the source of truth, it may have been removed by hand
+	status.at_login = launchAtLogin()
+	checkLogin := widget.NewCheck("Launch Netrunner at login", nil)
+	checkLogin.SetChecked(status.at_login)
+	checkLogin.OnChanged = func(b bool) {
+		if b == status.at_login {
+			return
+		}
+
+		if err := setLaunchAtLogin(b); err != nil {
+			globals.Logger.Error(err, "[Netrunner] Could not change launch at login")
+			dialog.ShowError(err, a.window)
+			checkLogin.SetChecked(status.at_login)
+			return
+		}
+		status.at_login = b
+		saveSettings()
+	}
+
+	radRPCLabel := canvas.NewText("RPC  BIND", colors.red)
+	radRPCLabel.TextSize = 10
+	radRPCLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rpcAddressLabel := canvas.NewText("RPC  ADDRESS", colors.red)
+	rpcAddressLabel.TextSize = 10
+	rpcAddressLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rpcAllowLabel := canvas.NewText("RPC  ALLOWLIST", colors.red)
+	rpcAllowLabel.TextSize = 10
+	rpcAllowLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rpcAuthLabel := canvas.NewText("RPC  AUTHENTICATION", colors.red)
+	rpcAuthLabel.TextSize = 10
+	rpcAuthLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	rpcWarning := canvas.NewText("RPC will be reachable from the internet", colors.yellow)
+	rpcWarning.TextSize = 10
+	rpcWarning.Hide()
+
+	rpcAddress := widget.NewEntry()
+	rpcAddress.SetPlaceHolder("0.0.0.0:" + strconv.Itoa(rpcPort()))
+
+	rpcAllow := widget.NewEntry()
+	rpcAllow.SetPlaceHolder("192.168.1.0/24, 10.0.0.5")
+	rpcAllow.Validator = func(s string) error {
+		_, err := parseAllowlist(s)
+		return err
+	}
+	rpcAllow.OnChanged = func(s string) {
+		status.rpc_allow = s
+	}
+
+	rpcUser := widget.NewEntry()
+	rpcUser.SetPlaceHolder("Username")
+	rpcUser.OnChanged = func(s string) {
+		status.rpc_user = s
+	}
+
+	rpcPass := widget.NewPasswordEntry()
+	rpcPass.SetPlaceHolder("Password")
+	rpcPass.OnChanged = func(s string) {
+		status.rpc_pass = s
+	}
+
+	radRPC := widget.NewRadioGroup([]string{RPC_BIND_LOOPBACK, RPC_BIND_LAN, RPC_BIND_CUSTOM}, nil)
+
+	// Shows the address the RPC will bind to and warns if it is public
+	rpcPreview := func() {
+		if status.rpc_mode != RPC_BIND_CUSTOM {
+			if bind, err := rpcBindAddress(status.rpc_mode, "", rpcPort()); err == nil {
+				rpcAddress.SetText(bind)
+			} else {
+				rpcAddress.SetText("")
+			}
+		}
+
+		if isPublicBind(rpcAddress.Text) {
+			rpcWarning.Show()
+		} else {
+			rpcWarning.Hide()
+		}
+	}
+
+	rpcAddress.Validator = func(s string) error {
+		_, err := rpcBindAddress(RPC_BIND_CUSTOM, s, 0)
+		return err
+	}
+	rpcAddress.OnChanged = func(s string) {
+		if status.rpc_mode == RPC_BIND_CUSTOM {
+			status.rpc_bind = s
+		}
+
+		if isPublicBind(s) {
+			rpcWarning.Show()
+		} else {
+			rpcWarning.Hide()
+		}
+	}
+
+	radRPC.OnChanged = func(s string) {
+		status.rpc_mode = s
+		if s == RPC_BIND_CUSTOM {
+			rpcAddress.Enable()
+			rpcAddress.SetText(status.rpc_bind)
+		} else {
+			rpcAddress.Disable()
+		}
+		rpcPreview()
+	}
+
+	if bind, ok := argument("--rpc-bind").(string); ok {
+		status.rpc_bind = bind
+		radRPC.SetSelected(RPC_BIND_CUSTOM)
+	} else if status.rpc_mode != "" {
+		radRPC.SetSelected(status.rpc_mode)
+	} else {
+		radRPC.SetSelected(RPC_BIND_LOOPBACK)
+	}
+
+	rpcAllow.SetText(status.rpc_allow)
+	rpcUser.SetText(status.rpc_user)
+	rpcPass.SetText(status.rpc_pass)
+
+	// Show the settings kept for the network just selected
+	applyNetwork := func() {
+		rpcAddress.SetPlaceHolder("0.0.0.0:" + strconv.Itoa(rpcPort()))
+		if status.rpc_mode == "" {
+			status.rpc_mode = RPC_BIND_LOOPBACK
+		}
+		if radRPC.Selected == status.rpc_mode {
+			radRPC.OnChanged(status.rpc_mode)
+		} else {
+			radRPC.SetSelected(status.rpc_mode)
+		}
+
+		integrator := status.integrator
+		reward.SetText(integrator)
+		if integrator == "" {
+			reward.SetPlaceHolder("Enter a DERO Address")
+		}
+		status.integrator = integrator
+
+		if status.simulator {
+			simReset.Show()
+		} else {
+			simReset.Hide()
+		}
+	}
 
 	radNetwork.OnChanged = func(s string) {
-		if s == "Testnet" {
-			globals.Arguments["--testnet"] = true
-			status.network = true
+		name := strings.ToLower(s)
+		if name == networkName() {
+			return
+		}
+
+		current := networkLabels[networkName()]
+
+		if bw.chain == nil && !bw.ran {
+			setNetwork(name)
+			applyNetwork()
+			return
+		}
+
+		if status.paused {
+			dialog.ShowError(fmt.Errorf("the network cannot be switched while a snapshot is being exported"), a.window)
+			radNetwork.SetSelected(current)
+			return
+		}
+
+		msg := fmt.Sprintf("The daemon will be restarted on %s.", s)
+		if m.Mission == 1 {
+			msg = fmt.Sprintf("The miner and daemon will be restarted on %s.", s)
+		}
+
+		// derod cannot be started twice in one process
+		if bw.ran {
+			msg = fmt.Sprintf("Netrunner will close and start again on %s.", s)
+			if m.Mission == 1 {
+				msg += "\nThe daemon and miner will be started again."
+			} else if bw.chain != nil {
+				msg += "\nThe daemon will be started again."
+			}
+		}
+
+		title := "Switch Network"
+		if bw.ran {
+			title = "Relaunch Netrunner"
+		}
+
+		dialog.ShowConfirm(title, msg, func(ok bool) {
+			if !ok {
+				radNetwork.SetSelected(current)
+				return
+			}
+
+			go switchNetwork(name, applyNetwork)
+		}, a.window)
+	}
+
+	dataDirLabel := canvas.NewText("DATA  DIRECTORY", colors.red)
+	dataDirLabel.TextSize = 10
+	dataDirLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	dataDirPath := canvas.NewText(dataDir(), colors.white)
+	dataDirPath.TextSize = 12
+
+	diskUsageLabel := canvas.NewText("DISK  USAGE", colors.red)
+	diskUsageLabel.TextSize = 10
+	diskUsageLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	diskUsage := canvas.NewText("---", colors.white)
+	diskUsage.TextSize = 12
+
+	diskFreeText := canvas.NewText("---", colors.white)
+	diskFreeText.TextSize = 12
+
+	pruneLabel := canvas.NewText("PRUNE  HISTORY", colors.red)
+	pruneLabel.TextSize = 10
+	pruneLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	pruneKeep := widget.NewEntry()
+	pruneKeep.SetPlaceHolder("Blocks to keep")
+	pruneKeep.Validator = func(s string) error {
+		if s == "" {
+			return nil
+		}
+		n, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || n < MIN_PRUNE_KEEP {
+			return fmt.Errorf("keep at least %d blocks", MIN_PRUNE_KEEP)
+		}
+		return nil
+	}
+
+	// Sizes the chain store in the background, walking the block store can take a while
+	refreshUsage := func() {
+		dataDirPath.Text = dataDir()
+		dataDirPath.Refresh()
+		diskUsage.Text = "Scanning..."
+		diskUsage.Refresh()
+
+		go func() {
+			usage, err := getDiskUsage()
+			if err != nil && usage.total() == 0 {
+				diskUsage.Text = "No chain data"
+			} else {
+				diskUsage.Text = fmt.Sprintf("Total %s  |  State %s  |  Blocks %s  |  Topo %s",
+					blockchain.ByteCountIEC(usage.total()),
+					blockchain.ByteCountIEC(usage.balances),
+					blockchain.ByteCountIEC(usage.blocks),
+					blockchain.ByteCountIEC(usage.topo+usage.other))
+			}
+			diskUsage.Refresh()
+
+			if free, err := diskFree(dataDir()); err == nil {
+				status.disk_free = free
+				diskFreeText.Text = "Free " + blockchain.ByteCountIEC(int64(free))
+				if free < DISK_WARN_BYTES {
+					diskFreeText.Color = colors.yellow
+				} else {
+					diskFreeText.Color = colors.white
+				}
+			} else {
+				diskFreeText.Text = "Free space unknown"
+			}
+			diskFreeText.Refresh()
+		}()
+	}
+
+	btnDataDir := widget.NewButton("DIR", nil)
+	btnDataDir.OnTapped = func() {
+		dialog.ShowFolderOpen(func(uri fyne.ListableURI, err error) {
+			if err != nil || uri == nil {
+				return
+			}
+
+			from := dataDir()
+			to := uri.Path()
+
+			// Point derod at the new base directory
+			apply := func() {
+				status.data_dir = to
+				globals.Arguments["--data-dir"] = to
+				globals.Initialize()
+				saveSettings()
+				refreshUsage()
+			}
+
+			if !hasChain(from) || from == to {
+				apply()
+				return
+			}
+
+			dialog.ShowConfirm("Move Chain", fmt.Sprintf("Move the existing chain from\n%s\nto\n%s ?\n\nChoose No to start a new chain at the new location.", from, to), func(move bool) {
+				if !move {
+					apply()
+					return
+				}
+
+				bar := widget.NewProgressBar()
+				progress := dialog.NewCustomWithoutButtons("Moving Chain", bar, a.window)
+				progress.Show()
+
+				go func() {
+					err := moveChain(from, to, bar.SetValue)
+					progress.Hide()
+					if err != nil {
+						globals.Logger.Error(err, "[Netrunner] Could not move chain data")
+						dialog.ShowError(err, a.window)
+						return
+					}
+					apply()
+				}()
+			}, a.window)
+		}, a.window)
+	}
+
+	btnPrune := widget.NewButton("PRN", nil)
+	btnPrune.OnTapped = func() {
+		keep, err := strconv.ParseInt(pruneKeep.Text, 10, 64)
+		if err != nil || keep < MIN_PRUNE_KEEP {
+			dialog.ShowError(fmt.Errorf("Enter the number of recent blocks to keep (at least %d).", MIN_PRUNE_KEEP), a.window)
+			return
+		}
+
+		dialog.ShowConfirm("Prune History", fmt.Sprintf("Discard all blocks and state history except the last %d blocks?\nThis cannot be undone.", keep), func(ok bool) {
+			if !ok {
+				return
+			}
+
+			progress := dialog.NewCustomWithoutButtons("Pruning History", widget.NewProgressBarInfinite(), a.window)
+			progress.Show()
+
+			go func() {
+				err := pruneChain(keep)
+				progress.Hide()
+				if err != nil {
+					globals.Logger.Error(err, "[Netrunner] Could not prune chain")
+					dialog.ShowError(err, a.window)
+				}
+				refreshUsage()
+			}()
+		}, a.window)
+	}
+
+	btnResetSim.OnTapped = func() {
+		dialog.ShowConfirm("Reset Simulator", "Discard the simulator chain and test wallets?\nThey are created again when the simulator starts.", func(ok bool) {
+			if !ok {
+				return
+			}
+
+			progress := dialog.NewCustomWithoutButtons("Resetting Simulator", widget.NewProgressBarInfinite(), a.window)
+			progress.Show()
+
+			go func() {
+				running := bw.chain != nil
+				if running {
+					stopDaemon()
+					daemonUI.Wait()
+				}
+
+				err := resetSimulator()
+				progress.Hide()
+				if err != nil {
+					globals.Logger.Error(err, "[Netrunner] Could not reset simulator")
+					dialog.ShowError(err, a.window)
+				}
+				refreshUsage()
+
+				if running && err == nil {
+					runDaemon()
+				}
+			}()
+		}, a.window)
+	}
+
+	snapshotLabel := canvas.NewText("CHAIN  SNAPSHOT", colors.red)
+	snapshotLabel.TextSize = 10
+	snapshotLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	snapshotInfo := canvas.NewText("Export the local chain or bootstrap from a snapshot file", colors.gray)
+	snapshotInfo.TextSize = 12
+
+	snapshotResult := canvas.NewText("", colors.white)
+	snapshotResult.TextSize = 12
+
+	btnSnapshot := widget.NewButton("SNP", nil)
+	btnSnapshot.OnTapped = func() {
+		export := func() {
+			save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+				if err != nil || w == nil {
+					return
+				}
+
+				bar := widget.NewProgressBar()
+				progress := dialog.NewCustomWithoutButtons("Exporting Snapshot", bar, a.window)
+				progress.Show()
+
+				go func() {
+					manifest, err := exportSnapshot(w, bar.SetValue)
+					w.Close()
+					progress.Hide()
+					if err != nil {
+						globals.Logger.Error(err, "[Netrunner] Snapshot export failed")
+						dialog.ShowError(err, a.window)
+						return
+					}
+
+					snapshotResult.Text = fmt.Sprintf("Exported %s at height %d (%d files)", manifest.Network, manifest.Height, len(manifest.Files))
+					snapshotResult.Refresh()
+				}()
+			}, a.window)
+			save.SetFileName(snapshotName())
+			save.Show()
+		}
+
+		if bw.chain != nil {
+			dialog.ShowConfirm("Export Snapshot", "The daemon and miner will be paused while the chain is exported.\nContinue?", func(ok bool) {
+				if ok {
+					export()
+				}
+			}, a.window)
 		} else {
-			globals.Arguments["--testnet"] = false
-			status.network = false
+			export()
 		}
-		globals.Initialize()
-		reward.Validate()
+	}
+
+	btnBootstrap := widget.NewButton("BST", nil)
+	btnBootstrap.OnTapped = func() {
+		dialog.ShowFileOpen(func(r fyne.URIReadCloser, err error) {
+			if err != nil || r == nil {
+				return
+			}
+			name := r.URI().Path()
+			r.Close()
+
+			bar := widget.NewProgressBar()
+			progress := dialog.NewCustomWithoutButtons("Verifying Snapshot", bar, a.window)
+			progress.Show()
+
+			go func() {
+				manifest, err := importSnapshot(name, bar.SetValue)
+				progress.Hide()
+				if err != nil {
+					globals.Logger.Error(err, "[Netrunner] Snapshot bootstrap failed")
+					dialog.ShowError(err, a.window)
+					return
+				}
+
+				snapshotResult.Text = fmt.Sprintf("Bootstrapped %s at height %d, top %.16s...", manifest.Network, manifest.Height, manifest.TopHash)
+				snapshotResult.Refresh()
+				refreshUsage()
+			}()
+		}, a.window)
 	}
 
 	configThreadsLabel := canvas.NewText("MINING  THREADS", colors.red)
@@ -416,8 +1068,6 @@ func layoutMain() fyne.CanvasObject {
 	configThreads.Refresh()
 
 	// Resources
-	res.background.SetMinSize(fyne.NewSize(1100, 600))
-	res.background.Refresh()
 	res.daemon.SetMinSize(fyne.NewSize(50, 50))
 	res.daemon.Refresh()
 	res.miner.SetMinSize(fyne.NewSize(50, 50))
@@ -425,34 +1075,85 @@ func layoutMain() fyne.CanvasObject {
 	res.icon.SetMinSize(fyne.NewSize(45, 45))
 	res.icon.Refresh()
 
+	autoMined := false
+
 	btnStartDaemon.OnTapped = func() {
 		if status.active == 0 {
+			saveSettings()
+
+			// The simulator has no other source of blocks, it mines on every start
+			if status.simulator {
+				autoMined = false
+			}
+
 			status.uptime = "---"
 			status.version = "---"
 			if status.integrator != "" {
 				globals.Arguments["--integrator-address"] = status.integrator
+			} else {
+				globals.Arguments["--integrator-address"] = nil
 			}
 
-			if status.network {
-				globals.Arguments["--rpc-bind"] = "0.0.0.0:" + strconv.Itoa(DEFAULT_DAEMON_TESTNET_RPC_PORT)
-			} else {
-				globals.Arguments["--rpc-bind"] = "0.0.0.0:" + strconv.Itoa(DEFAULT_DAEMON_MAINNET_RPC_PORT)
+			bind, err := rpcBindAddress(status.rpc_mode, status.rpc_bind, rpcPort())
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
 			}
 
-			startDaemon()
-			daemonTitle.Text = "Offline"
-			daemonTitle.Color = colors.gray
-			daemonTitle.Refresh()
+			if err := acquireLock(globals.GetDataDirectory()); err != nil {
+				globals.Logger.Error(err, "[Netrunner] Data directory is locked, daemon not started")
+				dialog.ShowError(err, a.window)
+				return
+			}
+
+			if err := preflight(bind); err != nil {
+				releaseLock()
+				dialog.ShowError(err, a.window)
+				return
+			}
+
+			if err := setRPCBind(bind, status.rpc_allow, status.rpc_user, status.rpc_pass); err != nil {
+				releaseLock()
+				globals.Logger.Error(err, "[Netrunner] RPC bind failed, daemon not started")
+				dialog.ShowError(err, a.window)
+				return
+			}
+
+			status.ip_daemon = bind
+			status.rpc_public = isPublicBind(bind)
+			if status.rpc_public {
+				globals.Logger.Info("[Netrunner] WARNING: daemon RPC is publicly bound", "address", bind)
+			}
+
+			startDaemon()
+			daemonTitle.Text = "Offline"
+			daemonTitle.Color = colors.gray
+			daemonTitle.Refresh()
 			btnStartDaemon.Disable()
 
+			daemonUI.Add(1)
 			go func() {
-				for bw.chain != nil {
+				defer daemonUI.Done()
+
+				for chain := bw.chain; chain != nil; chain = bw.chain {
 					if m.Mission == 1 {
 						hashrateLabel.Color = colors.red
 						hashrateLabel.Refresh()
 						hashrate.Text = m.Hashrate
 						hashrate.Color = colors.red
 						hashrate.Refresh()
+						shareLabel.Color = colors.red
+						shareLabel.Refresh()
+						if pct := hashrateShare(m.Speed, status.net_hashrate); pct > 0 {
+							share.Text = fmt.Sprintf("%.4f%%", pct)
+							shareETA.Text = "mini  " + formatETA(miniblockETA(m.Speed, status.net_hashrate))
+						} else {
+							share.Text = "---"
+							shareETA.Text = ""
+						}
+						share.Color = colors.red
+						share.Refresh()
+						shareETA.Refresh()
 						threadsLabel.Color = colors.red
 						threadsLabel.Refresh()
 						threads.Text = strconv.Itoa(m.Threads)
@@ -476,6 +1177,13 @@ func layoutMain() fyne.CanvasObject {
 						hashrate.Text = "---"
 						hashrate.Color = colors.gray
 						hashrate.Refresh()
+						shareLabel.Color = colors.gray
+						shareLabel.Refresh()
+						share.Text = "---"
+						share.Color = colors.gray
+						share.Refresh()
+						shareETA.Text = ""
+						shareETA.Refresh()
 						threadsLabel.Color = colors.gray
 						threadsLabel.Refresh()
 						threads.Text = "---"
@@ -500,24 +1208,31 @@ func layoutMain() fyne.CanvasObject {
 						btnStartMiner.Refresh()
 					}
 
-					if status.network {
-						status.network = true
-						radNetwork.SetSelected("Testnet")
-						network.Text = "Testnet"
-						network.Refresh()
-						status.ip_daemon = GetIP().String() + ":" + strconv.Itoa(DEFAULT_DAEMON_TESTNET_RPC_PORT)
-						daemonIP.Text = status.ip_daemon
-						daemonIP.Refresh()
+					radNetwork.SetSelected(networkLabels[networkName()])
+					network.Text = networkLabels[networkName()]
+					network.Refresh()
+					if status.simulator {
+						minerIP.Text = simulatorGetwork()
+					} else {
+						minerIP.Text = GetIP().String() + ":" + strconv.Itoa(workPort())
+					}
+					daemonIP.Text = status.ip_daemon
+					daemonIP.Refresh()
+					radRPC.Disable()
+					rpcAddress.Disable()
+					rpcAllow.Disable()
+					rpcUser.Disable()
+					rpcPass.Disable()
+					btnDataDir.Disable()
+					btnBootstrap.Disable()
+					btnPrune.Disable()
+					pruneKeep.Disable()
+
+					if status.rpc_public {
+						rpcBadge.Show()
 					} else {
-						status.network = false
-						radNetwork.SetSelected("Mainnet")
-						network.Text = "Mainnet"
-						network.Refresh()
-						status.ip_daemon = GetIP().String() + ":" + strconv.Itoa(DEFAULT_DAEMON_MAINNET_RPC_PORT)
-						daemonIP.Text = status.ip_daemon
-						daemonIP.Refresh()
+						rpcBadge.Hide()
 					}
-					radNetwork.Disable()
 
 					if status.fastsync {
 						radSync.SetSelected("Fast")
@@ -526,10 +1241,15 @@ func layoutMain() fyne.CanvasObject {
 					}
 					radSync.Disable()
 
-					peerHeight, _ := p2p.Best_Peer_Height()
-					if int64(bw.chain.Get_Height()) != peerHeight {
+					peerHeight := networkHeight(chain)
+					if status.paused {
+						daemonTitle.Text = "Paused"
+						daemonTitle.Color = colors.yellow
+						daemonTitle.Refresh()
+						btnStartMiner.Disable()
+					} else if int64(chain.Get_Height()) != peerHeight {
 						progress := ""
-						percent := float64(bw.chain.Get_Height()) / float64(peerHeight) * 100
+						percent := float64(chain.Get_Height()) / float64(peerHeight) * 100
 						if percent <= 0 {
 							progress = ""
 						} else if percent >= 100 {
@@ -538,12 +1258,12 @@ func layoutMain() fyne.CanvasObject {
 							progress = fmt.Sprintf("%.2f", percent) + "%"
 						}
 
-						if bw.chain.Get_Height() == -1 && status.fastsync {
+						if chain.Get_Height() == -1 && status.fastsync {
 							status.bootstrap = true
 							daemonTitle.Text = "Finalizing Bootstrap... "
 							daemonTitle.Color = colors.white
 							/*
-								} else if peerHeight-bw.chain.Get_Height() > 20000 && status.fastsync && status.bootstrap {
+								} else if peerHeight-chain.Get_Height() > 20000 && status.fastsync && status.bootstrap {
 									daemonTitle.Text = "Error - Full Syncing... " + progress
 									daemonTitle.Color = colors.white
 								}
@@ -561,8 +1281,23 @@ func layoutMain() fyne.CanvasObject {
 						daemonTitle.Text = "Running"
 						daemonTitle.Color = colors.red
 						daemonTitle.Refresh()
+
 						btnStartMiner.Enable()
 
+						// Only once per launch, a miner stopped by hand stays stopped,
+						// or when it was running before a network switch. The simulator
+						// always mines, its blocks come from the integrated miner
+						if ((status.auto_mine || status.simulator) && !autoMined || resumeMiner) && m.Mission == 0 && (peerHeight > 0 || status.simulator) {
+							autoMined = true
+							resumeMiner = false
+							if status.integrator == "" && !status.simulator {
+								globals.Logger.Info("[Netrunner] Mining not started on sync, no mining address set")
+							} else {
+								globals.Logger.Info("[Netrunner] Daemon synced, starting miner", "height", peerHeight)
+								btnStartMiner.OnTapped()
+							}
+						}
+
 						if !a.explorer.Content().Visible() {
 							btnExplorer.Enable()
 						} else {
@@ -573,7 +1308,7 @@ func layoutMain() fyne.CanvasObject {
 					ver := strings.Split(status.version, ".DEROHE")
 					version.Text = ver[0]
 					uptime.Text = status.uptime
-					height.Text = fmt.Sprintf("%d / %d", bw.chain.Get_Height(), peerHeight)
+					height.Text = fmt.Sprintf("%d / %d", chain.Get_Height(), peerHeight)
 					btime.Text = fmt.Sprintf("%.2f", status.block_time)
 					diff.Text = fmt.Sprintf("%d", status.difficulty)
 					supply.Text = globals.FormatMoney(status.supply)
@@ -584,11 +1319,11 @@ func layoutMain() fyne.CanvasObject {
 					noffset.Text = status.offset_ntp
 					poffset.Text = status.offset_p2p
 
-					if bw.chain.Get_Height() > 50 {
+					if chain.Get_Height() > 50 {
 						btnRewind.Enable()
 					}
 
-					//reward.Text = fmt.Sprintf("%s", bw.chain.IntegratorAddress())
+					//reward.Text = fmt.Sprintf("%s", chain.IntegratorAddress())
 					//reward.Disable()
 					btnStartDaemon.Disable()
 					btnConfig.Enable()
@@ -653,21 +1388,66 @@ func layoutMain() fyne.CanvasObject {
 
 					time.Sleep(1 * time.Second)
 				}
+
+				// Daemon was stopped underneath us
+				daemonTitle.Text = "Offline"
+				daemonTitle.Color = colors.gray
+				daemonTitle.Refresh()
+				res.daemon.Resource = resourceDaemonOffPng
+				res.daemon.Refresh()
+				btnStartMiner.Disable()
+				btnRewind.Disable()
+				btnExplorer.Disable()
+				rpcBadge.Hide()
+
+				radSync.Enable()
+				radRPC.Enable()
+				if status.rpc_mode == RPC_BIND_CUSTOM {
+					rpcAddress.Enable()
+				}
+				rpcAllow.Enable()
+				rpcUser.Enable()
+				rpcPass.Enable()
+				btnDataDir.Enable()
+				btnBootstrap.Enable()
+				btnPrune.Enable()
+				pruneKeep.Enable()
+				btnStartDaemon.Enable()
 			}()
 		}
 	}
 
+	runDaemon = btnStartDaemon.OnTapped
+	runMiner = btnStartMiner.OnTapped
+
 	statusPanel := container.NewVBox(
 		rect1,
 		rectSpacer,
 		rectSpacer,
-		container.NewHBox(
-			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-			rectSpacer,
-			container.NewMax(
-				rect280,
+		container.NewBorder(
+			nil,
+			nil,
+			container.NewHBox(
+				rectSpacer,
+				rectSpacer,
+				rectSpacer,
+				rectSpacer,
+			),
+			container.NewHBox(
+				container.NewVBox(
+					container.NewMax(
+						btnRect2,
+						btnRewind,
+					),
+					container.NewMax(
+						btnRect2,
+						btnExplorer,
+					),
+				),
+				rect1,
+			),
+			newColumns(
+				[]float32{1, 1, 1},
 				container.NewVBox(
 					rectSpacer,
 					netLabel,
@@ -675,7 +1455,11 @@ func layoutMain() fyne.CanvasObject {
 					network,
 					rectSpacer,
 					rectSpacer,
-					endpoints,
+					container.NewHBox(
+						endpoints,
+						rectSpacer,
+						rpcBadge,
+					),
 					rectSpacer,
 					daemonIP,
 					rectSpacer,
@@ -689,9 +1473,6 @@ func layoutMain() fyne.CanvasObject {
 					rectSpacer,
 					uptime,
 				),
-			),
-			container.NewMax(
-				rect280,
 				container.NewVBox(
 					rectSpacer,
 					heightLabel,
@@ -713,9 +1494,6 @@ func layoutMain() fyne.CanvasObject {
 					rectSpacer,
 					peers,
 				),
-			),
-			container.NewMax(
-				rect280,
 				container.NewVBox(
 					rectSpacer,
 					minersLabel,
@@ -738,29 +1516,31 @@ func layoutMain() fyne.CanvasObject {
 					rpool,
 				),
 			),
-			layout.NewSpacer(),
-			container.NewMax(
-				container.NewVBox(
-					container.NewMax(
-						btnRect2,
-						btnRewind,
-					),
-					container.NewMax(
-						btnRect2,
-						btnExplorer,
-					),
-					container.NewMax(
-						btnRect2,
-						//btnGnomon,
-					),
-				),
-			),
-			rect1,
 		),
 	)
 
+	// Config rows share their column weights so the sections line up at any width
+	configRow := func(columns ...fyne.CanvasObject) fyne.CanvasObject {
+		for len(columns) < 3 {
+			columns = append(columns, layout.NewSpacer())
+		}
+
+		return container.NewBorder(
+			nil,
+			nil,
+			container.NewHBox(
+				rectSpacer,
+				rectSpacer,
+				rectSpacer,
+				rectSpacer,
+			),
+			rectSpacer,
+			newColumns([]float32{2, 5, 3}, columns...),
+		)
+	}
+
 	configPanel := container.NewMax(
-		container.NewVBox(
+		container.NewVScroll(container.NewVBox(
 			div3,
 			rectSpacer,
 			container.NewHBox(
@@ -775,182 +1555,385 @@ func layoutMain() fyne.CanvasObject {
 				rect1,
 			),
 			rectSpacer,
-			container.NewHBox(
-				rectSpacer,
-				rectSpacer,
-				rectSpacer,
-				rectSpacer,
-				container.NewMax(
-					rectLeft,
-					container.NewVBox(
-						rectSpacer,
-						radNetworkLabel,
-						rectSpacer,
-						radNetwork,
-						rectSpacer,
-						rectSpacer,
-						rectSpacer,
-						radSyncLabel,
+			configRow(
+				container.NewVBox(
+					rectSpacer,
+					radNetworkLabel,
+					rectSpacer,
+					radNetwork,
+					networkNote,
+					simReset,
+					rectSpacer,
+					rectSpacer,
+					rectSpacer,
+					radSyncLabel,
+					rectSpacer,
+					radSync,
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					rewardLabel,
+					rectSpacer,
+					reward,
+					rectSpacer,
+					rectSpacer,
+					rectSpacer,
+					rectSpacer,
+					rectSpacer,
+					rectSpacer,
+					rect1,
+					container.NewHBox(
+						configThreadsLabel,
 						rectSpacer,
-						radSync,
 						rectSpacer,
+						configThreadsCount,
+					),
+					rectSpacer,
+					rectSpacer,
+					container.NewMax(
+						rectSlider,
+						configThreads,
 					),
+					rectSpacer,
 				),
-				rectSpacer,
-				rectSpacer,
-				container.NewMax(
-					rectMid,
-					container.NewVBox(
-						rectSpacer,
-						rewardLabel,
-						rectSpacer,
-						reward,
-						rectSpacer,
-						rectSpacer,
-						rectSpacer,
-						rectSpacer,
-						rectSpacer,
-						rectSpacer,
-						rect1,
-						container.NewHBox(
-							configThreadsLabel,
-							rectSpacer,
-							rectSpacer,
-							configThreadsCount,
+			),
+			rectSpacer,
+			configRow(
+				container.NewVBox(
+					rectSpacer,
+					radRPCLabel,
+					rectSpacer,
+					radRPC,
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					rpcAddressLabel,
+					rectSpacer,
+					rpcAddress,
+					rectSpacer,
+					rpcWarning,
+					rectSpacer,
+					rpcAllowLabel,
+					rectSpacer,
+					rpcAllow,
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					rpcAuthLabel,
+					rectSpacer,
+					rpcUser,
+					rectSpacer,
+					rpcPass,
+					rectSpacer,
+				),
+			),
+			rectSpacer,
+			configRow(
+				container.NewVBox(
+					rectSpacer,
+					dataDirLabel,
+					rectSpacer,
+					container.NewHBox(
+						container.NewMax(
+							btnRect2,
+							btnDataDir,
 						),
-						rectSpacer,
-						rectSpacer,
+					),
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					dataDirPath,
+					rectSpacer,
+					rectSpacer,
+					diskUsageLabel,
+					rectSpacer,
+					diskUsage,
+					rectSpacer,
+					diskFreeText,
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					pruneLabel,
+					rectSpacer,
+					pruneKeep,
+					rectSpacer,
+					container.NewHBox(
 						container.NewMax(
-							rectSlider,
-							configThreads,
+							btnRect2,
+							btnPrune,
 						),
-						rectSpacer,
 					),
+					rectSpacer,
 				),
 			),
-		),
+			rectSpacer,
+			configRow(
+				container.NewVBox(
+					rectSpacer,
+					snapshotLabel,
+					rectSpacer,
+					container.NewHBox(
+						container.NewMax(
+							btnRect2,
+							btnSnapshot,
+						),
+						container.NewMax(
+							btnRect2,
+							btnBootstrap,
+						),
+					),
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					snapshotInfo,
+					rectSpacer,
+					snapshotResult,
+					rectSpacer,
+				),
+			),
+			rectSpacer,
+			configRow(
+				container.NewVBox(
+					rectSpacer,
+					launchLabel,
+					rectSpacer,
+					checkLogin,
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					checkAutoStart,
+					checkAutoMine,
+					rectSpacer,
+					launchNote,
+					rectSpacer,
+				),
+				container.NewVBox(
+					rectSpacer,
+					radCloseLabel,
+					rectSpacer,
+					radClose,
+					rectSpacer,
+				),
+			),
+		)),
 	)
 
 	bodyBox := container.NewMax(
 		statusPanel,
 	)
 
+	btnReturn.OnTapped = func() {
+		saveSettings()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statusPanel)
+		bodyBox.Refresh()
+	}
+
+	var logPanel fyne.CanvasObject
+	logPanel = layoutLogs(func() {
+		logPanel.Hide()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statusPanel)
+		bodyBox.Refresh()
+	})
+	logPanel.Hide()
+
+	var chartPanel fyne.CanvasObject
+	chartPanel = layoutCharts(func() {
+		chartPanel.Hide()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statusPanel)
+		bodyBox.Refresh()
+	})
+	chartPanel.Hide()
+
+	var statsPanel fyne.CanvasObject
+	statsPanel = layoutStats(func() {
+		statsPanel.Hide()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statusPanel)
+		bodyBox.Refresh()
+	})
+	statsPanel.Hide()
+
+	var walletPanel fyne.CanvasObject
+	walletPanel = layoutWallet(func() {
+		walletPanel.Hide()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statusPanel)
+		bodyBox.Refresh()
+	})
+	walletPanel.Hide()
+
+	var contractsPanel fyne.CanvasObject
+	contractsPanel = layoutContracts(func() {
+		contractsPanel.Hide()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statusPanel)
+		bodyBox.Refresh()
+	})
+	contractsPanel.Hide()
+
 	btnConfig.OnTapped = func() {
+		refreshUsage()
+		refreshNetworkNote()
+		logPanel.Hide()
+		chartPanel.Hide()
+		statsPanel.Hide()
+		walletPanel.Hide()
+		contractsPanel.Hide()
 		bodyBox.RemoveAll()
 		bodyBox.AddObject(configPanel)
 		bodyBox.Refresh()
 	}
 
-	btnReturn.OnTapped = func() {
+	btnWallet.OnTapped = func() {
+		logPanel.Hide()
+		chartPanel.Hide()
+		statsPanel.Hide()
+		contractsPanel.Hide()
+		walletPanel.Show()
 		bodyBox.RemoveAll()
-		bodyBox.AddObject(statusPanel)
+		bodyBox.AddObject(walletPanel)
 		bodyBox.Refresh()
 	}
 
-	daemonBox := container.NewHBox(
-		rectSpacer,
-		res.daemon,
-		rectSpacer,
-		daemonTitle,
-		rectSpacer,
-		layout.NewSpacer(),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectCell,
-		container.NewMax(
-			rectCell,
-			container.NewHBox(
-				noffsetLabel,
-				rectSpacer,
-				rectSpacer,
-				noffset,
-				layout.NewSpacer(),
-			),
-		),
-		rectSpacer,
-		rectSpacer,
-		container.NewMax(
-			rectCell,
-			container.NewHBox(
-				poffsetLabel,
-				rectSpacer,
-				rectSpacer,
-				poffset,
-				layout.NewSpacer(),
+	btnContracts.OnTapped = func() {
+		logPanel.Hide()
+		chartPanel.Hide()
+		statsPanel.Hide()
+		walletPanel.Hide()
+		contractsPanel.Show()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(contractsPanel)
+		bodyBox.Refresh()
+	}
+
+	btnStats.OnTapped = func() {
+		logPanel.Hide()
+		chartPanel.Hide()
+		walletPanel.Hide()
+		contractsPanel.Hide()
+		statsPanel.Show()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(statsPanel)
+		bodyBox.Refresh()
+	}
+
+	btnCharts.OnTapped = func() {
+		logPanel.Hide()
+		statsPanel.Hide()
+		walletPanel.Hide()
+		contractsPanel.Hide()
+		chartPanel.Show()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(chartPanel)
+		bodyBox.Refresh()
+	}
+
+	btnLogs.OnTapped = func() {
+		chartPanel.Hide()
+		statsPanel.Hide()
+		walletPanel.Hide()
+		contractsPanel.Hide()
+		logPanel.Show()
+		bodyBox.RemoveAll()
+		bodyBox.AddObject(logPanel)
+		bodyBox.Refresh()
+	}
+
+	// Both rows use the same weights so the miner stats sit under the daemon's
+	statusWeights := []float32{3, 2, 2, 2, 2, 1.5}
+
+	newStat := func(label fyne.CanvasObject, value ...fyne.CanvasObject) fyne.CanvasObject {
+		row := container.NewHBox(label, rectSpacer, rectSpacer)
+		for _, v := range value {
+			row.Add(v)
+		}
+		row.Add(layout.NewSpacer())
+
+		return row
+	}
+
+	newRun := func(btn fyne.CanvasObject) fyne.CanvasObject {
+		return container.NewHBox(
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				btn,
 			),
+			rectSpacer,
+		)
+	}
+
+	daemonBox := newColumns(
+		statusWeights,
+		container.NewHBox(
+			rectSpacer,
+			res.daemon,
+			rectSpacer,
+			daemonTitle,
 		),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		container.NewMax(
-			btnRect,
-			btnStartDaemon,
+		layout.NewSpacer(),
+		layout.NewSpacer(),
+		newStat(noffsetLabel, noffset),
+		newStat(poffsetLabel, poffset),
+		newRun(btnStartDaemon),
+	)
+
+	minerBox := newColumns(
+		statusWeights,
+		container.NewHBox(
+			rectSpacer,
+			res.miner,
+			rectSpacer,
+			minerTitle,
 		),
-		rectSpacer,
+		newStat(shareLabel, share, rectSpacer, container.NewVBox(layout.NewSpacer(), shareETA)),
+		newStat(hashrateLabel, hashrate),
+		newStat(threadsLabel, threads),
+		newStat(blocksLabel, blocks),
+		newRun(btnStartMiner),
 	)
 
-	minerBox := container.NewHBox(
+	topBox := container.NewHBox(
 		rectSpacer,
-		res.miner,
+		rect50,
+		title,
 		rectSpacer,
-		minerTitle,
 		layout.NewSpacer(),
-		rectSpacer,
-		rectSpacer,
-		rectSpacer,
-		rectCell,
 		container.NewMax(
-			rectCell,
-			container.NewHBox(
-				hashrateLabel,
-				rectSpacer,
-				rectSpacer,
-				hashrate,
-				layout.NewSpacer(),
-			),
+			btnRect,
+			btnWallet,
 		),
-		layout.NewSpacer(),
-		rectSpacer,
 		rectSpacer,
 		container.NewMax(
-			rectCell,
-			container.NewHBox(
-				threadsLabel,
-				rectSpacer,
-				rectSpacer,
-				threads,
-				layout.NewSpacer(),
-			),
+			btnRect,
+			btnContracts,
 		),
 		rectSpacer,
-		rectSpacer,
 		container.NewMax(
-			rectCell,
-			container.NewHBox(
-				blocksLabel,
-				rectSpacer,
-				rectSpacer,
-				blocks,
-				layout.NewSpacer(),
-			),
+			btnRect,
+			btnStats,
 		),
 		rectSpacer,
-		rectSpacer,
-		rectSpacer,
 		container.NewMax(
 			btnRect,
-			btnStartMiner,
+			btnCharts,
 		),
 		rectSpacer,
-	)
-
-	topBox := container.NewHBox(
-		rectSpacer,
-		rect50,
-		title,
+		container.NewMax(
+			btnRect,
+			btnLogs,
+		),
 		rectSpacer,
-		layout.NewSpacer(),
 		container.NewMax(
 			btnRect,
 			btnConfig,
@@ -976,21 +1959,18 @@ func layoutMain() fyne.CanvasObject {
 		rectSpacer,
 	)
 
-	left := container.NewMax(
-		frame,
-		bodyBox,
-	)
-
 	c := container.NewBorder(
 		top,
 		form,
-		left,
 		nil,
+		nil,
+		bodyBox,
 	)
 
+	// Scrolls on screens smaller than the layout, grows with larger windows
 	layout := container.NewMax(
 		res.background,
-		c,
+		newWindowScroll(c),
 	)
 
 	return layout
@@ -999,29 +1979,14 @@ func layoutMain() fyne.CanvasObject {
 func layoutExplorer() fyne.CanvasObject {
 	loadResources()
 
-	rect := canvas.NewRectangle(color.Transparent)
-	rect.SetMinSize(fyne.NewSize(680, 300))
-
 	rect50 := canvas.NewRectangle(color.Transparent)
 	rect50.SetMinSize(fyne.NewSize(0, 50))
 
 	btnRect := canvas.NewRectangle(color.Transparent)
 	btnRect.SetMinSize(fyne.NewSize(110, 50))
 
-	rect280 := canvas.NewRectangle(color.Transparent)
-	rect280.SetMinSize(fyne.NewSize(300, 300))
-
 	div := canvas.NewRectangle(colors.red)
-	div.SetMinSize(fyne.NewSize(1000, 2))
-
-	div2 := canvas.NewRectangle(colors.gray)
-	div2.SetMinSize(fyne.NewSize(1000, 1))
-
-	frame := canvas.NewRectangle(color.Transparent)
-	frame.SetMinSize(fyne.NewSize(1000, 300))
-
-	rectCell := canvas.NewRectangle(color.Transparent)
-	rectCell.SetMinSize(fyne.NewSize(150, 5))
+	div.SetMinSize(fyne.NewSize(0, 2))
 
 	rectSpacer := canvas.NewRectangle(color.Transparent)
 	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
@@ -1060,17 +2025,2443 @@ func layoutExplorer() fyne.CanvasObject {
 		rectSpacer,
 	)
 
-	c := container.NewBorder(
-		top,
-		nil,
-		nil,
-		nil,
+	newLabel := func(text string) *canvas.Text {
+		label := canvas.NewText(text, colors.red)
+		label.TextSize = 10
+		label.TextStyle = fyne.TextStyle{Bold: true}
+		return label
+	}
+
+	newLine := func() fyne.CanvasObject {
+		line := canvas.NewText("", colors.white)
+		line.TextSize = 11
+		line.TextStyle = fyne.TextStyle{Monospace: true}
+		return line
+	}
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(0, 200))
+
+	txLabel := newLabel("TRANSACTION")
+
+	txHash := widget.NewEntry()
+	txHash.SetPlaceHolder("Transaction hash")
+
+	txInfo := canvas.NewText("Decode any transaction known to the local node", colors.gray)
+	txInfo.TextSize = 12
+
+	txGrid := container.NewGridWithColumns(4)
+
+	newCell := func(grid *fyne.Container, name string) *canvas.Text {
+		value := canvas.NewText("---", colors.white)
+		value.TextSize = 14
+
+		grid.Add(container.NewVBox(
+			rectSpacer,
+			newLabel(name),
+			value,
+		))
+
+		return value
+	}
+
+	txType := newCell(txGrid, "TYPE")
+	txState := newCell(txGrid, "STATUS")
+	txHeight := newCell(txGrid, "BLOCK  HEIGHT")
+	txTopo := newCell(txGrid, "TOPO  HEIGHT")
+	txFees := newCell(txGrid, "FEES")
+	txSize := newCell(txGrid, "SIZE")
+	txProof := newCell(txGrid, "PROOF  SIZE")
+	txPayloads := newCell(txGrid, "PAYLOADS")
+
+	var lines []string
+	var args []string
+
+	payloadsLabel := newLabel("PAYLOADS  AND  RING  MEMBERS")
+	payloadList := widget.NewList(
+		func() int {
+			return len(lines)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(lines) {
+				return
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = lines[i]
+			line.Refresh()
+		},
 	)
 
-	layout := container.NewMax(
-		res.background,
-		c,
+	argsLabel := newLabel("SC  ARGUMENTS")
+	argList := widget.NewList(
+		func() int {
+			return len(args)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(args) {
+				return
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = args[i]
+			line.Refresh()
+		},
 	)
 
-	return layout
+	rawLabel := newLabel("RAW  HEX")
+	raw := widget.NewLabel("")
+	raw.Wrapping = fyne.TextWrapBreak
+	raw.TextStyle = fyne.TextStyle{Monospace: true}
+
+	btnCopy := widget.NewButton("CPY", nil)
+	btnCopy.Disable()
+	btnCopy.OnTapped = func() {
+		a.explorer.Clipboard().SetContent(raw.Text)
+	}
+
+	set := func(t *canvas.Text, value string) {
+		t.Text = value
+		t.Refresh()
+	}
+
+	btnDecode := widget.NewButton("DEC", nil)
+	btnDecode.OnTapped = func() {
+		d, err := decodeTransaction(txHash.Text)
+		if err != nil {
+			dialog.ShowError(err, a.explorer)
+			return
+		}
+
+		state := "Mined"
+		switch {
+		case d.pool != "":
+			state = "In " + d.pool
+		case d.block == "":
+			state = "Not applied"
+		}
+
+		height, topo := "---", "---"
+		if d.height >= 0 {
+			height = strconv.FormatInt(d.height, 10)
+		}
+		if d.topo >= 0 {
+			topo = strconv.FormatInt(d.topo, 10)
+		}
+
+		set(txType, d.kind)
+		set(txState, state)
+		set(txHeight, height)
+		set(txTopo, topo)
+		set(txFees, globals.FormatMoney(d.fees)+" DERO")
+		set(txSize, blockchain.ByteCountIEC(int64(d.size)))
+		set(txProof, blockchain.ByteCountIEC(int64(d.proof_size)))
+		set(txPayloads, strconv.Itoa(len(d.payloads)))
+
+		info := fmt.Sprintf("Version %d", d.version)
+		if d.block != "" {
+			info += ", block " + d.block
+		}
+		if d.signer != "" {
+			info += ", signer " + d.signer
+		}
+		if len(d.invalid) > 0 {
+			info += fmt.Sprintf(", skipped by %d blocks", len(d.invalid))
+		}
+		txInfo.Text = info
+		txInfo.Refresh()
+
+		lines = nil
+		for i, p := range d.payloads {
+			asset := "DERO"
+			if p.scid != "" {
+				asset = p.scid
+			}
+			lines = append(lines, fmt.Sprintf("#%d  %s  ring %d  burn %s  fees %s  payload %dB  proof %s", i, asset, p.ring_size, globals.FormatMoney(p.burn), globals.FormatMoney(p.fees), p.rpc_size, blockchain.ByteCountIEC(int64(p.proof_size))))
+			for _, member := range p.ring {
+				lines = append(lines, "    "+member)
+			}
+		}
+		payloadList.Refresh()
+
+		args = d.sc_args
+		if d.code != "" {
+			args = append(args, strings.Split(strings.ReplaceAll(d.code, "\r\n", "\n"), "\n")...)
+		}
+		argList.Refresh()
+
+		raw.SetText(d.raw)
+		btnCopy.Enable()
+	}
+	txHash.OnSubmitted = func(string) { btnDecode.OnTapped() }
+
+	blockLabel := newLabel("BLOCK")
+
+	blockRef := widget.NewEntry()
+	blockRef.SetPlaceHolder("Block hash or topo height")
+
+	blockInfo := canvas.NewText("Tap a block in the DAG or look one up", colors.gray)
+	blockInfo.TextSize = 12
+
+	blockGrid := container.NewGridWithColumns(4)
+
+	blHeight := newCell(blockGrid, "HEIGHT")
+	blTopo := newCell(blockGrid, "TOPO  HEIGHT")
+	blTime := newCell(blockGrid, "TIME")
+	blState := newCell(blockGrid, "STATE")
+	blDifficulty := newCell(blockGrid, "DIFFICULTY")
+	blMiniblocks := newCell(blockGrid, "MINIBLOCKS")
+	blReward := newCell(blockGrid, "REWARD")
+	blTxs := newCell(blockGrid, "TRANSACTIONS")
+
+	var tips []string
+	var blockTxs []string
+
+	var btnBlock *widget.Button
+
+	tipsLabel := newLabel("TIPS")
+	tipList := widget.NewList(
+		func() int {
+			return len(tips)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(tips) {
+				return
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = tips[i]
+			line.Refresh()
+		},
+	)
+	tipList.OnSelected = func(i widget.ListItemID) {
+		tipList.UnselectAll()
+		if i < len(tips) {
+			blockRef.SetText(tips[i])
+			btnBlock.OnTapped()
+		}
+	}
+
+	blockTxLabel := newLabel("BLOCK  TRANSACTIONS")
+	blockTxList := widget.NewList(
+		func() int {
+			return len(blockTxs)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(blockTxs) {
+				return
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = blockTxs[i]
+			line.Refresh()
+		},
+	)
+	blockTxList.OnSelected = func(i widget.ListItemID) {
+		blockTxList.UnselectAll()
+		if i < len(blockTxs) {
+			txHash.SetText(blockTxs[i])
+			btnDecode.OnTapped()
+		}
+	}
+
+	rectTips := canvas.NewRectangle(color.Transparent)
+	rectTips.SetMinSize(fyne.NewSize(0, 80))
+
+	btnBlock = widget.NewButton("BLK", nil)
+	btnBlock.OnTapped = func() {
+		d, err := decodeBlock(blockRef.Text)
+		if err != nil {
+			dialog.ShowError(err, a.explorer)
+			return
+		}
+
+		topo, state := "---", "Not in topo order"
+		if d.topo >= 0 {
+			topo = strconv.FormatInt(d.topo, 10)
+			state = "Main"
+			if d.side {
+				state = "Side"
+			}
+		}
+		if d.stable {
+			state += ", stable"
+		} else {
+			state += ", unstable"
+		}
+
+		set(blHeight, strconv.FormatInt(d.height, 10))
+		set(blTopo, topo)
+		set(blTime, d.timestamp.Format("2006-01-02 15:04:05"))
+		set(blState, state)
+		set(blDifficulty, strconv.FormatUint(d.difficulty, 10))
+		set(blMiniblocks, strconv.Itoa(d.miniblocks))
+		set(blReward, globals.FormatMoney(d.reward)+" DERO")
+		set(blTxs, strconv.Itoa(len(d.txs)))
+
+		info := d.hash
+		if d.miner != "" {
+			info += ", integrator " + d.miner
+		}
+		blockInfo.Text = info
+		blockInfo.Refresh()
+
+		tips = d.tips
+		tipList.Refresh()
+		blockTxs = d.txs
+		blockTxList.Refresh()
+	}
+	blockRef.OnSubmitted = func(string) { btnBlock.OnTapped() }
+
+	dagLabel := newLabel("DAG")
+
+	dagInfo := canvas.NewText("Red blocks extend the chain, yellow are side blocks, the shaded region is stable", colors.gray)
+	dagInfo.TextSize = 12
+
+	dag := NewDAG()
+	dag.OnTapped = func(n DAGNode) {
+		blockRef.SetText(n.hash.String())
+		btnBlock.OnTapped()
+	}
+
+	dagScroll := container.NewHScroll(dag)
+
+	radDAG := widget.NewRadioGroup(dagWindows, nil)
+	radDAG.Horizontal = true
+	radDAG.SetSelected(strconv.Itoa(DAG_BLOCKS))
+
+	following := true
+	refreshDAG := func() {
+		n, err := strconv.ParseInt(radDAG.Selected, 10, 64)
+		if err != nil {
+			n = DAG_BLOCKS
+		}
+
+		// Keep the newest blocks in view unless the user scrolled back
+		if !following && dagScroll.Offset.X+dagScroll.Size().Width >= dag.MinSize().Width-DAG_COLUMN {
+			following = true
+		}
+		dag.SetNodes(loadDAG(bw.chain, n))
+		if following {
+			dagScroll.Offset.X = fyne.Max(0, dag.MinSize().Width-dagScroll.Size().Width)
+			dagScroll.Refresh()
+		}
+	}
+	dagScroll.OnScrolled = func(p fyne.Position) {
+		following = p.X+dagScroll.Size().Width >= dag.MinSize().Width-DAG_COLUMN
+	}
+	radDAG.OnChanged = func(string) {
+		following = true
+		refreshDAG()
+	}
+
+	exportLabel := newLabel("CHAIN  EXPORT")
+
+	exportInfo := canvas.NewText("Stream a height range of the local chain to CSV or NDJSON, the top is used when TO is empty", colors.gray)
+	exportInfo.TextSize = 12
+
+	exportResult := canvas.NewText("", colors.white)
+	exportResult.TextSize = 12
+
+	exportFrom := widget.NewEntry()
+	exportFrom.SetPlaceHolder("From height")
+	exportFrom.SetText("0")
+
+	exportTo := widget.NewEntry()
+	exportTo.SetPlaceHolder("To height")
+
+	exportKind := widget.NewSelect(exportKinds, nil)
+	exportKind.SetSelected(EXPORT_BLOCKS)
+
+	exportFormatSelect := widget.NewSelect(exportFormats, nil)
+	exportFormatSelect.SetSelected(EXPORT_CSV)
+
+	btnExport := widget.NewButton("DMP", nil)
+	btnExport.OnTapped = func() {
+		opts := ExportOptions{kind: exportKind.Selected, format: exportFormatSelect.Selected, to: -1}
+
+		from, err := strconv.ParseInt(strings.TrimSpace(exportFrom.Text), 10, 64)
+		if err != nil || from < 0 {
+			dialog.ShowError(fmt.Errorf("enter a valid from height"), a.explorer)
+			return
+		}
+		opts.from = from
+
+		if to := strings.TrimSpace(exportTo.Text); to != "" {
+			if opts.to, err = strconv.ParseInt(to, 10, 64); err != nil || opts.to < from {
+				dialog.ShowError(fmt.Errorf("enter a to height at or above %d", from), a.explorer)
+				return
+			}
+		}
+
+		chain := bw.chain
+		if chain == nil {
+			dialog.ShowError(fmt.Errorf("daemon is not running"), a.explorer)
+			return
+		}
+
+		save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+			if err != nil || w == nil {
+				return
+			}
+
+			bar := widget.NewProgressBar()
+			progress := dialog.NewCustomWithoutButtons("Exporting Chain", bar, a.explorer)
+			progress.Show()
+			btnExport.Disable()
+
+			go func() {
+				rows, skipped, err := exportChain(chain, w, opts, bar.SetValue)
+				if cerr := w.Close(); err == nil {
+					err = cerr
+				}
+				progress.Hide()
+				btnExport.Enable()
+				if err != nil {
+					globals.Logger.Error(err, "[Netrunner] Chain export failed", "kind", opts.kind)
+					dialog.ShowError(err, a.explorer)
+					return
+				}
+
+				globals.Logger.Info("[Netrunner] Chain exported", "kind", opts.kind, "rows", rows, "skipped", skipped)
+				exportResult.Text = fmt.Sprintf("Exported %d %s rows to %s (%d pruned or unreadable skipped)", rows, opts.kind, w.URI().Name(), skipped)
+				exportResult.Refresh()
+			}()
+		}, a.explorer)
+		save.SetFileName(exportName(opts))
+		save.Show()
+	}
+
+	decoder := container.NewVBox(
+		container.NewHBox(
+			dagLabel,
+			layout.NewSpacer(),
+			radDAG,
+		),
+		dagInfo,
+		dagScroll,
+		rectSpacer,
+		blockLabel,
+		container.NewBorder(
+			nil,
+			nil,
+			nil,
+			container.NewMax(
+				btnRect,
+				btnBlock,
+			),
+			blockRef,
+		),
+		blockInfo,
+		blockGrid,
+		rectSpacer,
+		container.NewGridWithColumns(2,
+			container.NewVBox(
+				tipsLabel,
+				container.NewMax(
+					rectTips,
+					tipList,
+				),
+			),
+			container.NewVBox(
+				blockTxLabel,
+				container.NewMax(
+					rectTips,
+					blockTxList,
+				),
+			),
+		),
+		rectSpacer,
+		txLabel,
+		container.NewBorder(
+			nil,
+			nil,
+			nil,
+			container.NewMax(
+				btnRect,
+				btnDecode,
+			),
+			txHash,
+		),
+		txInfo,
+		txGrid,
+		rectSpacer,
+		payloadsLabel,
+		container.NewMax(
+			rectList,
+			payloadList,
+		),
+		rectSpacer,
+		argsLabel,
+		container.NewMax(
+			rectList,
+			argList,
+		),
+		rectSpacer,
+		container.NewHBox(
+			rawLabel,
+			layout.NewSpacer(),
+			btnCopy,
+		),
+		raw,
+		rectSpacer,
+		exportLabel,
+		exportInfo,
+		container.NewBorder(
+			nil,
+			nil,
+			nil,
+			container.NewMax(
+				btnRect,
+				btnExport,
+			),
+			container.NewGridWithColumns(4,
+				exportFrom,
+				exportTo,
+				exportKind,
+				exportFormatSelect,
+			),
+		),
+		exportResult,
+		rectSpacer,
+	)
+
+	c := container.NewBorder(
+		top,
+		nil,
+		rectSpacer,
+		rectSpacer,
+		decoder,
+	)
+
+	layout := container.NewMax(
+		res.background,
+		newWindowScroll(c),
+	)
+
+	// The DAG follows the chain while this content is the explorer's
+	go func() {
+		for {
+			time.Sleep(2 * time.Second)
+			if a.explorer.Content() != layout {
+				return
+			}
+			if a.explorer.Content().Visible() && bw.chain != nil {
+				refreshDAG()
+			}
+		}
+	}()
+
+	return layout
+}
+
+func layoutLogs(back func()) fyne.CanvasObject {
+	rect50 := canvas.NewRectangle(color.Transparent)
+	rect50.SetMinSize(fyne.NewSize(0, 50))
+
+	btnRect := canvas.NewRectangle(color.Transparent)
+	btnRect.SetMinSize(fyne.NewSize(110, 50))
+
+	rectSelect := canvas.NewRectangle(color.Transparent)
+	rectSelect.SetMinSize(fyne.NewSize(150, 5))
+
+	rectMinutes := canvas.NewRectangle(color.Transparent)
+	rectMinutes.SetMinSize(fyne.NewSize(80, 5))
+
+	rectLevel := canvas.NewRectangle(color.Transparent)
+	rectLevel.SetMinSize(fyne.NewSize(80, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(0, 200))
+
+	div := canvas.NewRectangle(colors.gray)
+	div.SetMinSize(fyne.NewSize(0, 1))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	logsTitle := canvas.NewText("Logs", colors.red)
+	logsTitle.TextStyle = fyne.TextStyle{Bold: true}
+	logsTitle.TextSize = 25
+
+	levelLabel := canvas.NewText("LEVEL", colors.red)
+	levelLabel.TextSize = 10
+	levelLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	subsystemLabel := canvas.NewText("SUBSYSTEM", colors.red)
+	subsystemLabel.TextSize = 10
+	subsystemLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	searchLabel := canvas.NewText("SEARCH", colors.red)
+	searchLabel.TextSize = 10
+	searchLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	exportLabel := canvas.NewText("EXPORT  LAST  MINUTES", colors.red)
+	exportLabel.TextSize = 10
+	exportLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	clogLabel := canvas.NewText("CONSOLE  LOG  LEVEL", colors.red)
+	clogLabel.TextSize = 10
+	clogLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	flogLabel := canvas.NewText("FILE  LOG  LEVEL", colors.red)
+	flogLabel.TextSize = 10
+	flogLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	count := canvas.NewText("", colors.gray)
+	count.TextSize = 10
+
+	var shown []LogEntry
+	following := true
+
+	list := widget.NewList(
+		func() int {
+			return len(shown)
+		},
+		func() fyne.CanvasObject {
+			line := canvas.NewText("", colors.white)
+			line.TextSize = 11
+			line.TextStyle = fyne.TextStyle{Monospace: true}
+			return line
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(shown) {
+				return
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = shown[i].String()
+			switch {
+			case shown[i].level >= 2:
+				line.Color = colors.red
+			case shown[i].level == 1:
+				line.Color = colors.yellow
+			case shown[i].level == 0:
+				line.Color = colors.white
+			default:
+				line.Color = colors.gray
+			}
+			line.Refresh()
+		},
+	)
+
+	selLevel := widget.NewSelect(logLevels, nil)
+	selLevel.SetSelected("Info")
+
+	selSubsystem := widget.NewSelect(logSubsystems, nil)
+	selSubsystem.SetSelected("All")
+
+	search := widget.NewEntry()
+	search.SetPlaceHolder("Text to find")
+
+	update := func() {
+		min := logLevelValues[selLevel.Selected]
+		subsystem := selSubsystem.Selected
+		text := strings.ToLower(search.Text)
+
+		shown = logs.filter(func(e LogEntry) bool {
+			if e.level < min {
+				return false
+			}
+
+			if subsystem != "All" && e.subsystem() != subsystem {
+				return false
+			}
+
+			return text == "" || strings.Contains(strings.ToLower(e.msg+" "+e.fields), text)
+		})
+
+		count.Text = fmt.Sprintf("%d entries", len(shown))
+		count.Refresh()
+		list.Refresh()
+		if following {
+			list.ScrollToBottom()
+		}
+	}
+
+	selLevel.OnChanged = func(s string) { update() }
+	selSubsystem.OnChanged = func(s string) { update() }
+	search.OnChanged = func(s string) { update() }
+
+	btnFollow := widget.NewButton("PSE", nil)
+	btnFollow.OnTapped = func() {
+		following = !following
+		if following {
+			btnFollow.SetText("PSE")
+			update()
+		} else {
+			btnFollow.SetText("FLW")
+		}
+	}
+
+	minutes := widget.NewEntry()
+	minutes.SetText("15")
+	minutes.Validator = func(s string) error {
+		if n, err := strconv.Atoi(s); err != nil || n < 1 {
+			return fmt.Errorf("minutes must be a positive number")
+		}
+		return nil
+	}
+
+	btnExport := widget.NewButton("SAV", nil)
+	btnExport.OnTapped = func() {
+		n, err := strconv.Atoi(minutes.Text)
+		if err != nil || n < 1 {
+			dialog.ShowError(fmt.Errorf("Minutes must be a positive number"), a.window)
+			return
+		}
+
+		save := dialog.NewFileSave(func(w fyne.URIWriteCloser, err error) {
+			if err != nil || w == nil {
+				return
+			}
+			defer w.Close()
+
+			written, err := exportLogs(w, time.Duration(n)*time.Minute)
+			if err != nil {
+				dialog.ShowError(err, a.window)
+				return
+			}
+
+			globals.Logger.Info("[Netrunner] Logs exported", "path", w.URI().Path(), "entries", written)
+			dialog.ShowInformation("Logs Exported", fmt.Sprintf("%d entries written to\n%s", written, w.URI().Path()), a.window)
+		}, a.window)
+		save.SetFileName(fmt.Sprintf("netrunner_logs_%s.txt", time.Now().Format("20060102_1504")))
+		save.Show()
+	}
+
+	var levels []string
+	for i := 0; i <= 9; i++ {
+		levels = append(levels, strconv.Itoa(i))
+	}
+
+	selClog := widget.NewSelect(levels, nil)
+	selFlog := widget.NewSelect(levels, nil)
+
+	current := func(arg string, fallback string) string {
+		if globals.Arguments[arg] != nil {
+			return globals.Arguments[arg].(string)
+		}
+		return fallback
+	}
+	selClog.SetSelected(current("--clog-level", "0"))
+	selFlog.SetSelected(current("--flog-level", "1"))
+
+	applyLevels := func(s string) {
+		console, _ := strconv.Atoi(selClog.Selected)
+		file, _ := strconv.Atoi(selFlog.Selected)
+		setLogLevels(console, file)
+		saveSettings()
+	}
+	selClog.OnChanged = applyLevels
+	selFlog.OnChanged = applyLevels
+
+	btnReturn := widget.NewButton("RTN", nil)
+	btnReturn.OnTapped = back
+
+	controls := container.NewVBox(
+		container.NewHBox(
+			rectSpacer,
+			rect50,
+			logsTitle,
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				btnReturn,
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+		container.NewBorder(
+			nil,
+			nil,
+			container.NewHBox(
+				rectSpacer,
+				rectSpacer,
+				container.NewVBox(
+					levelLabel,
+					rectSpacer,
+					container.NewMax(
+						rectSelect,
+						selLevel,
+					),
+				),
+				rectSpacer,
+				container.NewVBox(
+					subsystemLabel,
+					rectSpacer,
+					container.NewMax(
+						rectSelect,
+						selSubsystem,
+					),
+				),
+				rectSpacer,
+			),
+			container.NewHBox(
+				rectSpacer,
+				container.NewVBox(
+					layout.NewSpacer(),
+					count,
+				),
+				rectSpacer,
+				container.NewVBox(
+					layout.NewSpacer(),
+					container.NewMax(
+						btnRect,
+						btnFollow,
+					),
+				),
+				rectSpacer,
+			),
+			container.NewVBox(
+				searchLabel,
+				rectSpacer,
+				search,
+			),
+		),
+		rectSpacer,
+		div,
+	)
+
+	bottom := container.NewVBox(
+		div,
+		rectSpacer,
+		container.NewHBox(
+			rectSpacer,
+			rectSpacer,
+			container.NewVBox(
+				exportLabel,
+				rectSpacer,
+				container.NewHBox(
+					container.NewMax(
+						rectMinutes,
+						minutes,
+					),
+					rectSpacer,
+					container.NewMax(
+						btnRect,
+						btnExport,
+					),
+				),
+			),
+			layout.NewSpacer(),
+			container.NewVBox(
+				clogLabel,
+				rectSpacer,
+				container.NewMax(
+					rectLevel,
+					selClog,
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			container.NewVBox(
+				flogLabel,
+				rectSpacer,
+				container.NewMax(
+					rectLevel,
+					selFlog,
+				),
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+	)
+
+	panel := container.NewMax(
+		rectList,
+		container.NewBorder(
+			controls,
+			bottom,
+			rectSpacer,
+			rectSpacer,
+			list,
+		),
+	)
+
+	// Only refresh while the pane is open and following
+	go func() {
+		for {
+			time.Sleep(time.Second)
+			if following && panel.Visible() {
+				update()
+			}
+		}
+	}()
+
+	return panel
+}
+
+func layoutCharts(back func()) fyne.CanvasObject {
+	rect50 := canvas.NewRectangle(color.Transparent)
+	rect50.SetMinSize(fyne.NewSize(0, 50))
+
+	btnRect := canvas.NewRectangle(color.Transparent)
+	btnRect.SetMinSize(fyne.NewSize(110, 50))
+
+	div := canvas.NewRectangle(colors.gray)
+	div.SetMinSize(fyne.NewSize(0, 1))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	chartsTitle := canvas.NewText("Charts", colors.red)
+	chartsTitle.TextStyle = fyne.TextStyle{Bold: true}
+	chartsTitle.TextSize = 25
+
+	rangeLabel := canvas.NewText("RANGE", colors.red)
+	rangeLabel.TextSize = 10
+	rangeLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	radRange := widget.NewRadioGroup([]string{"1h", "24h", "7d"}, nil)
+	radRange.Horizontal = true
+
+	checkDisk := widget.NewCheck("Keep history on disk", nil)
+	checkDisk.SetChecked(status.metrics_disk)
+	checkDisk.OnChanged = func(b bool) {
+		status.metrics_disk = b
+		openMetrics(b)
+		saveSettings()
+	}
+
+	type chartCell struct {
+		metric Metric
+		value  *canvas.Text
+		span   *canvas.Text
+		chart  *Chart
+	}
+
+	var cells []chartCell
+	grid := container.NewGridWithColumns(2)
+
+	for _, metric := range chartMetrics {
+		label := canvas.NewText(metric.label, colors.red)
+		label.TextSize = 10
+		label.TextStyle = fyne.TextStyle{Bold: true}
+
+		value := canvas.NewText("---", colors.white)
+		value.TextSize = 14
+
+		span := canvas.NewText("", colors.gray)
+		span.TextSize = 10
+
+		cell := chartCell{metric: metric, value: value, span: span, chart: NewChart(colors.red)}
+		cells = append(cells, cell)
+
+		grid.Add(container.NewVBox(
+			rectSpacer,
+			container.NewHBox(
+				label,
+				layout.NewSpacer(),
+				span,
+			),
+			value,
+			cell.chart,
+		))
+	}
+
+	update := func() {
+		list := metrics.since(time.Now().Add(-chartRanges[radRange.Selected]))
+
+		for _, c := range cells {
+			c.chart.SetPoints(downsample(list, c.metric.value, CHART_POINTS))
+
+			if len(list) == 0 {
+				c.value.Text = "---"
+				c.span.Text = ""
+			} else {
+				min, max := c.metric.value(list[0]), c.metric.value(list[0])
+				for _, s := range list {
+					v := c.metric.value(s)
+					if v < min {
+						min = v
+					}
+					if v > max {
+						max = v
+					}
+				}
+
+				c.value.Text = c.metric.format(c.metric.value(list[len(list)-1]))
+				c.span.Text = fmt.Sprintf("min %s  max %s", c.metric.format(min), c.metric.format(max))
+			}
+			c.value.Refresh()
+			c.span.Refresh()
+		}
+	}
+
+	radRange.OnChanged = func(s string) { update() }
+	radRange.SetSelected("1h")
+
+	btnReturn := widget.NewButton("RTN", nil)
+	btnReturn.OnTapped = back
+
+	top := container.NewVBox(
+		container.NewHBox(
+			rectSpacer,
+			rect50,
+			chartsTitle,
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				btnReturn,
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+		container.NewHBox(
+			rectSpacer,
+			rectSpacer,
+			container.NewVBox(
+				rangeLabel,
+				radRange,
+			),
+			layout.NewSpacer(),
+			container.NewVBox(
+				layout.NewSpacer(),
+				checkDisk,
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+		div,
+	)
+
+	panel := container.NewBorder(
+		top,
+		nil,
+		rectSpacer,
+		rectSpacer,
+		container.NewVScroll(grid),
+	)
+
+	// Samples are taken every few seconds, refresh at the same pace while open
+	go func() {
+		for {
+			time.Sleep(METRICS_INTERVAL / 2)
+			if panel.Visible() {
+				update()
+			}
+		}
+	}()
+
+	return panel
+}
+
+func layoutStats(back func()) fyne.CanvasObject {
+	rect50 := canvas.NewRectangle(color.Transparent)
+	rect50.SetMinSize(fyne.NewSize(0, 50))
+
+	btnRect := canvas.NewRectangle(color.Transparent)
+	btnRect.SetMinSize(fyne.NewSize(110, 50))
+
+	div := canvas.NewRectangle(colors.gray)
+	div.SetMinSize(fyne.NewSize(0, 1))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	statsTitle := canvas.NewText("Statistics", colors.red)
+	statsTitle.TextStyle = fyne.TextStyle{Bold: true}
+	statsTitle.TextSize = 25
+
+	emissionLabel := canvas.NewText("EMISSION", colors.red)
+	emissionLabel.TextSize = 14
+	emissionLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	blocksLabel := canvas.NewText("BLOCKS", colors.red)
+	blocksLabel.TextSize = 14
+	blocksLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	windowLabel := canvas.NewText("WINDOW", colors.red)
+	windowLabel.TextSize = 10
+	windowLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	radWindow := widget.NewRadioGroup([]string{"50", "200", "1000", "5000"}, nil)
+	radWindow.Horizontal = true
+
+	miningLabel := canvas.NewText("MINING", colors.red)
+	miningLabel.TextSize = 14
+	miningLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	hashrateLabel := canvas.NewText("HASHRATE  H/S", colors.red)
+	hashrateLabel.TextSize = 10
+	hashrateLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	hashrateEntry := widget.NewEntry()
+	hashrateEntry.SetPlaceHolder("Live")
+
+	wattsLabel := canvas.NewText("POWER  WATTS", colors.red)
+	wattsLabel.TextSize = 10
+	wattsLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	wattsEntry := widget.NewEntry()
+	wattsEntry.SetPlaceHolder("0")
+	if status.watts > 0 {
+		wattsEntry.SetText(strconv.FormatFloat(status.watts, 'f', -1, 64))
+	}
+
+	priceLabel := canvas.NewText("PRICE  PER  KWH", colors.red)
+	priceLabel.TextSize = 10
+	priceLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	priceEntry := widget.NewEntry()
+	priceEntry.SetPlaceHolder("0.00")
+	if status.kwh_price > 0 {
+		priceEntry.SetText(strconv.FormatFloat(status.kwh_price, 'f', -1, 64))
+	}
+
+	// Blank or invalid input counts as zero
+	parse := func(s string) float64 {
+		v, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		if err != nil || v < 0 {
+			return 0
+		}
+		return v
+	}
+
+	type statCell struct {
+		value *canvas.Text
+		note  *canvas.Text
+	}
+
+	grid := container.NewGridWithColumns(2)
+	blockGrid := container.NewGridWithColumns(2)
+	miningGrid := container.NewGridWithColumns(2)
+
+	newCell := func(grid *fyne.Container, name string) statCell {
+		label := canvas.NewText(name, colors.red)
+		label.TextSize = 10
+		label.TextStyle = fyne.TextStyle{Bold: true}
+
+		value := canvas.NewText("---", colors.white)
+		value.TextSize = 14
+
+		note := canvas.NewText("", colors.gray)
+		note.TextSize = 10
+
+		grid.Add(container.NewVBox(
+			rectSpacer,
+			container.NewHBox(
+				label,
+				layout.NewSpacer(),
+				note,
+			),
+			value,
+		))
+
+		return statCell{value: value, note: note}
+	}
+
+	supply := newCell(grid, "CIRCULATING  SUPPLY")
+	side := newCell(grid, "SIDE  BLOCK  REWARDS")
+	reward := newCell(grid, "BLOCK  REWARD")
+	share := newCell(grid, "MINIBLOCK  REWARD")
+	integrator := newCell(grid, "INTEGRATOR  REWARD")
+	daily := newCell(grid, "EMISSION  PER  DAY")
+	yearly := newCell(grid, "EMISSION  PER  YEAR")
+	inflation := newCell(grid, "INFLATION")
+	halving := newCell(grid, "NEXT  REWARD  REDUCTION")
+
+	mean := newCell(blockGrid, "AVERAGE  BLOCK  TIME")
+	median := newCell(blockGrid, "MEDIAN  BLOCK  TIME")
+	p95 := newCell(blockGrid, "95TH  PERCENTILE")
+	sideRate := newCell(blockGrid, "SIDE  BLOCK  RATE")
+	minis := newCell(blockGrid, "MINIBLOCKS  PER  BLOCK")
+	difficulty := newCell(blockGrid, "DIFFICULTY")
+	netHashrate := newCell(blockGrid, "NETWORK  HASHRATE")
+	localShare := newCell(blockGrid, "OUR  SHARE")
+
+	var earnings []statCell
+	for _, name := range []string{"EXPECTED  PER  HOUR", "EXPECTED  PER  DAY", "EXPECTED  PER  WEEK"} {
+		earnings = append(earnings, newCell(miningGrid, name))
+	}
+	session := newCell(miningGrid, "SESSION  MINIBLOCKS")
+
+	diffLabel := canvas.NewText("DIFFICULTY  TREND", colors.red)
+	diffLabel.TextSize = 10
+	diffLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	diffChart := NewChart(colors.red)
+
+	histLabel := canvas.NewText("BLOCK  TIME  DISTRIBUTION", colors.red)
+	histLabel.TextSize = 10
+	histLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	histNote := canvas.NewText(fmt.Sprintf("%ds bins, target %ds marked", ANALYTICS_BIN_SECONDS, TARGET_BLOCK_TIME), colors.gray)
+	histNote.TextSize = 10
+
+	histogram := NewHistogram(colors.red, colors.green, targetBin())
+
+	histMin := canvas.NewText("0s", colors.gray)
+	histMin.TextSize = 10
+
+	histMax := canvas.NewText(fmt.Sprintf("%ds+", (ANALYTICS_BINS-1)*ANALYTICS_BIN_SECONDS), colors.gray)
+	histMax.TextSize = 10
+
+	minersLabel := canvas.NewText("MINERS", colors.red)
+	minersLabel.TextSize = 14
+	minersLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	periodLabel := canvas.NewText("PERIOD", colors.red)
+	periodLabel.TextSize = 10
+	periodLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	radPeriod := widget.NewRadioGroup([]string{"Hour", "Day"}, nil)
+	radPeriod.Horizontal = true
+
+	minersInfo := canvas.NewText("", colors.gray)
+	minersInfo.TextSize = 12
+
+	rectMiners := canvas.NewRectangle(color.Transparent)
+	rectMiners.SetMinSize(fyne.NewSize(0, 250))
+
+	var ranks []MinerRank
+
+	minerList := widget.NewList(
+		func() int {
+			return len(ranks)
+		},
+		func() fyne.CanvasObject {
+			line := canvas.NewText("", colors.white)
+			line.TextSize = 11
+			line.TextStyle = fyne.TextStyle{Monospace: true}
+			return line
+		},
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(ranks) {
+				return
+			}
+
+			r := ranks[i]
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%4d  %6d  %6.2f%%  %5d  %s", i+1, r.miniblocks, r.share, r.final, r.name())
+			line.Color = colors.white
+			if r.ours {
+				line.Color = colors.green
+			}
+			line.Refresh()
+		},
+	)
+
+	minersHeader := canvas.NewText(fmt.Sprintf("%4s  %6s  %7s  %5s  %s", "#", "MINIS", "SHARE", "FINAL", "ADDRESS  OR  KEY  HASH"), colors.gray)
+	minersHeader.TextSize = 11
+	minersHeader.TextStyle = fyne.TextStyle{Monospace: true}
+
+	updateMiners := func() {
+		var ours [16]byte
+		if addr, err := miningAddress(); err == nil {
+			var key [33]byte
+			copy(key[:], addr.PublicKey.EncodeCompressed())
+			ours = keyHash(key)
+		}
+
+		period := leaderboardPeriods[radPeriod.Selected]
+		list, total, blocks, covered := analytics.leaderboard(period, ours)
+		resolveMiners(bw.chain, list)
+
+		info := fmt.Sprintf("%d miniblocks in %d blocks by %d miners", total, blocks, len(list))
+		for i, r := range list {
+			if r.ours {
+				info += fmt.Sprintf(", our rank %d", i+1)
+				break
+			}
+		}
+		if covered < period {
+			info += fmt.Sprintf(", history covers %s so far", covered.Round(time.Minute))
+		}
+		if len(list) == 0 {
+			info = "No blocks sampled yet"
+		}
+
+		minersInfo.Text = info
+		minersInfo.Refresh()
+
+		ranks = list
+		minerList.Refresh()
+	}
+
+	set := func(c statCell, value string, note string) {
+		c.value.Text = value
+		c.note.Text = note
+		c.value.Refresh()
+		c.note.Refresh()
+	}
+
+	update := func() {
+		if bw.chain == nil {
+			for _, c := range []statCell{supply, side, reward, share, integrator, daily, yearly, inflation, halving, mean, median, p95, sideRate, minis, difficulty, netHashrate, localShare, session} {
+				set(c, "---", "")
+			}
+			for _, c := range earnings {
+				set(c, "---", "")
+			}
+			diffChart.SetPoints(nil)
+			histogram.SetBins(nil)
+			ranks = nil
+			minerList.Refresh()
+			minersInfo.Text = ""
+			minersInfo.Refresh()
+			return
+		}
+
+		updateMiners()
+
+		b := analytics.stats(analyticsWindows[radWindow.Selected])
+		note := fmt.Sprintf("%d blocks", b.blocks)
+
+		set(mean, fmt.Sprintf("%.2fs", b.mean), note)
+		set(median, fmt.Sprintf("%.2fs", b.median), fmt.Sprintf("target %ds", TARGET_BLOCK_TIME))
+		set(p95, fmt.Sprintf("%.2fs", b.p95), "")
+		set(sideRate, fmt.Sprintf("%.2f%%", b.side_rate), fmt.Sprintf("%d side blocks", b.side))
+		set(minis, fmt.Sprintf("%.2f", b.miniblocks), fmt.Sprintf("of %d", MINIBLOCKS_PER_BLOCK))
+		set(difficulty, fmt.Sprintf("%d", b.difficulty), fmt.Sprintf("%+.2f%% over window", b.trend))
+		set(netHashrate, formatHashrate(b.hashrate), "from block difficulty")
+
+		if m.Mission == 1 && b.hashrate > 0 {
+			set(localShare, fmt.Sprintf("%.4f%%", hashrateShare(m.Speed, b.hashrate)), "miniblock every "+formatETA(miniblockETA(m.Speed, b.hashrate)))
+		} else {
+			set(localShare, "---", "miner not running")
+		}
+
+		diffChart.SetPoints(downsampleValues(b.difficulties, CHART_POINTS))
+
+		local := parse(hashrateEntry.Text)
+		if local == 0 && m.Mission == 1 {
+			local = m.Speed
+		}
+
+		for i, period := range earningPeriods {
+			x := expectedEarnings(local, status.net_hashrate, status.emission.reward, status.watts, status.kwh_price, period)
+			note := fmt.Sprintf("%.2f miniblocks", x.miniblocks)
+			if x.cost > 0 {
+				note += fmt.Sprintf("  power %.2f", x.cost)
+			}
+			set(earnings[i], globals.FormatMoney(uint64(x.dero))+" DERO", note)
+		}
+
+		if m.Mission == 1 {
+			found := m.Blocks + m.MiniBlocks
+			expected := expectedMiniblocks(metrics.since(m.Started), m.Started)
+			note := fmt.Sprintf("%.2f expected", expected)
+			if expected > 0 {
+				note += fmt.Sprintf("  luck %.0f%%", luck(found, expected))
+			}
+			set(session, fmt.Sprintf("%d found", found), note)
+		} else {
+			set(session, "---", "miner not running")
+		}
+		histogram.SetBins(b.histogram)
+
+		e := status.emission
+
+		if e.exact {
+			set(supply, globals.FormatMoney(e.supply)+" DERO", "counted from chain")
+			set(side, globals.FormatMoney(e.supply-e.scheduled)+" DERO", "above schedule")
+		} else {
+			set(supply, globals.FormatMoney(e.supply)+" DERO", "by schedule")
+			set(side, "---", "counting blocks")
+		}
+
+		set(reward, globals.FormatMoney(e.reward)+" DERO", fmt.Sprintf("height %d", e.height))
+		set(share, globals.FormatMoney(e.share)+" DERO", fmt.Sprintf("%d per block", MINIBLOCKS_PER_BLOCK))
+		set(integrator, globals.FormatMoney(e.integrator)+" DERO", "final miniblock")
+		set(daily, globals.FormatMoney(e.per_day)+" DERO", fmt.Sprintf("%d blocks", BLOCKS_PER_DAY))
+		set(yearly, globals.FormatMoney(e.per_year)+" DERO", fmt.Sprintf("%d blocks", BLOCKS_PER_YEAR))
+		set(inflation, fmt.Sprintf("%.2f%%", e.inflation), "per year")
+
+		days := float64(e.next_halving-e.height) / float64(BLOCKS_PER_DAY)
+		set(halving, fmt.Sprintf("height %d", e.next_halving), fmt.Sprintf("about %.0f days", days))
+	}
+
+	wattsEntry.OnChanged = func(s string) {
+		status.watts = parse(s)
+		update()
+	}
+
+	priceEntry.OnChanged = func(s string) {
+		status.kwh_price = parse(s)
+		update()
+	}
+
+	hashrateEntry.OnChanged = func(s string) { update() }
+
+	radWindow.OnChanged = func(s string) { update() }
+	radWindow.SetSelected("200")
+
+	radPeriod.OnChanged = func(s string) { update() }
+	radPeriod.SetSelected("Hour")
+
+	btnReturn := widget.NewButton("RTN", nil)
+	btnReturn.OnTapped = func() {
+		saveSettings()
+		back()
+	}
+
+	top := container.NewVBox(
+		container.NewHBox(
+			rectSpacer,
+			rect50,
+			statsTitle,
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				btnReturn,
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+		div,
+	)
+
+	panel := container.NewBorder(
+		top,
+		nil,
+		rectSpacer,
+		rectSpacer,
+		container.NewVScroll(container.NewVBox(
+			rectSpacer,
+			emissionLabel,
+			grid,
+			rectSpacer,
+			rectSpacer,
+			container.NewHBox(
+				container.NewVBox(
+					layout.NewSpacer(),
+					blocksLabel,
+				),
+				layout.NewSpacer(),
+				container.NewVBox(
+					windowLabel,
+					radWindow,
+				),
+			),
+			blockGrid,
+			rectSpacer,
+			container.NewGridWithColumns(2,
+				container.NewVBox(
+					diffLabel,
+					diffChart,
+				),
+				container.NewVBox(
+					container.NewHBox(
+						histLabel,
+						layout.NewSpacer(),
+						histNote,
+					),
+					histogram,
+					container.NewHBox(
+						histMin,
+						layout.NewSpacer(),
+						histMax,
+					),
+				),
+			),
+			rectSpacer,
+			rectSpacer,
+			miningLabel,
+			container.NewGridWithColumns(3,
+				container.NewVBox(
+					hashrateLabel,
+					hashrateEntry,
+				),
+				container.NewVBox(
+					wattsLabel,
+					wattsEntry,
+				),
+				container.NewVBox(
+					priceLabel,
+					priceEntry,
+				),
+			),
+			miningGrid,
+			rectSpacer,
+			rectSpacer,
+			container.NewHBox(
+				container.NewVBox(
+					layout.NewSpacer(),
+					minersLabel,
+				),
+				layout.NewSpacer(),
+				container.NewVBox(
+					periodLabel,
+					radPeriod,
+				),
+			),
+			minersInfo,
+			minersHeader,
+			container.NewMax(
+				rectMiners,
+				minerList,
+			),
+			rectSpacer,
+		)),
+	)
+
+	go func() {
+		for {
+			time.Sleep(time.Second)
+			if panel.Visible() {
+				update()
+			}
+		}
+	}()
+
+	return panel
+}
+
+func layoutWallet(back func()) fyne.CanvasObject {
+	rect50 := canvas.NewRectangle(color.Transparent)
+	rect50.SetMinSize(fyne.NewSize(0, 50))
+
+	btnRect := canvas.NewRectangle(color.Transparent)
+	btnRect.SetMinSize(fyne.NewSize(110, 50))
+
+	div := canvas.NewRectangle(colors.gray)
+	div.SetMinSize(fyne.NewSize(0, 1))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(0, 200))
+
+	walletTitle := canvas.NewText("Wallet", colors.red)
+	walletTitle.TextStyle = fyne.TextStyle{Bold: true}
+	walletTitle.TextSize = 25
+
+	addressLabel := canvas.NewText("MINING  ADDRESS", colors.red)
+	addressLabel.TextSize = 10
+	addressLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	address := canvas.NewText("---", colors.white)
+	address.TextSize = 12
+	address.TextStyle = fyne.TextStyle{Monospace: true}
+
+	walletInfo := canvas.NewText("Open the full wallet file of the mining address, it is only read to show its balance and transfers", colors.gray)
+	walletInfo.TextSize = 12
+
+	type walletCell struct {
+		value *canvas.Text
+		note  *canvas.Text
+	}
+
+	grid := container.NewGridWithColumns(2)
+
+	newCell := func(name string) walletCell {
+		label := canvas.NewText(name, colors.red)
+		label.TextSize = 10
+		label.TextStyle = fyne.TextStyle{Bold: true}
+
+		value := canvas.NewText("---", colors.white)
+		value.TextSize = 14
+
+		note := canvas.NewText("", colors.gray)
+		note.TextSize = 10
+
+		grid.Add(container.NewVBox(
+			rectSpacer,
+			container.NewHBox(
+				label,
+				layout.NewSpacer(),
+				note,
+			),
+			value,
+		))
+
+		return walletCell{value: value, note: note}
+	}
+
+	balance := newCell("BALANCE")
+	locked := newCell("LOCKED")
+	earned := newCell("REWARDS  EARNED")
+	wstatus := newCell("WALLET  STATUS")
+
+	set := func(c walletCell, value string, note string) {
+		c.value.Text = value
+		c.note.Text = note
+		c.value.Refresh()
+		c.note.Refresh()
+	}
+
+	rewardsLabel := canvas.NewText("RECENT  REWARDS", colors.red)
+	rewardsLabel.TextSize = 10
+	rewardsLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	transfersLabel := canvas.NewText("RECENT  TRANSFERS", colors.red)
+	transfersLabel.TextSize = 10
+	transfersLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	var rewards []Reward
+	var transfers []derorpc.Entry
+
+	newLine := func() fyne.CanvasObject {
+		line := canvas.NewText("", colors.white)
+		line.TextSize = 11
+		line.TextStyle = fyne.TextStyle{Monospace: true}
+		return line
+	}
+
+	rewardList := widget.NewList(
+		func() int {
+			return len(rewards)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(rewards) {
+				return
+			}
+
+			r := rewards[i]
+			kind := "miniblock"
+			if r.integrator {
+				kind = "integrator"
+			}
+			if r.side {
+				kind += " side"
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%-9d %2dx  %-16s %s", r.height, r.miniblocks, kind, globals.FormatMoney(r.amount))
+			line.Refresh()
+		},
+	)
+
+	transferList := widget.NewList(
+		func() int {
+			return len(transfers)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(transfers) {
+				return
+			}
+
+			t := transfers[i]
+			kind, sign := "out", "-"
+			switch {
+			case t.Coinbase:
+				kind, sign = "coinbase", "+"
+			case t.Incoming:
+				kind, sign = "in", "+"
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%-9d %-8s %s%s  %.16s", t.Height, kind, sign, globals.FormatMoney(t.Amount), t.TXID)
+			line.Color = colors.white
+			if !t.Incoming && !t.Coinbase {
+				line.Color = colors.gray
+			}
+			line.Refresh()
+		},
+	)
+
+	gatewayLabel := canvas.NewText("DAPP  GATEWAY", colors.red)
+	gatewayLabel.TextSize = 10
+	gatewayLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	checkGateway := widget.NewCheck(fmt.Sprintf("Accept dApp connections on ws://%s:%d%s", DEFAULT_DAEMON_LOCAL_ADDRESS, XSWD_PORT, XSWD_PATH), nil)
+	checkGateway.SetChecked(status.gateway)
+	checkGateway.OnChanged = func(b bool) {
+		if b == gateway.running() {
+			return
+		}
+
+		if b {
+			if err := startGateway(); err != nil {
+				globals.Logger.Error(err, "[Netrunner] Could not start dApp gateway")
+				dialog.ShowError(err, a.window)
+				checkGateway.SetChecked(false)
+				return
+			}
+		} else {
+			go stopGateway()
+		}
+
+		status.gateway = b
+		saveSettings()
+	}
+
+	gatewayInfo := canvas.NewText("Applications use the dApp wallet only with your approval, transfers are confirmed every time", colors.gray)
+	gatewayInfo.TextSize = 12
+
+	dappLabel := canvas.NewText("DAPP  WALLET", colors.red)
+	dappLabel.TextSize = 10
+	dappLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	dappAddress := canvas.NewText("---", colors.white)
+	dappAddress.TextSize = 12
+	dappAddress.TextStyle = fyne.TextStyle{Monospace: true}
+
+	btnDappOpen := widget.NewButton("OPN", nil)
+	btnDappClose := widget.NewButton("CLS", nil)
+	btnDappClose.Disable()
+
+	appsLabel := canvas.NewText("REGISTERED  APPS", colors.red)
+	appsLabel.TextSize = 10
+	appsLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	auditLabel := canvas.NewText("GATEWAY  AUDIT", colors.red)
+	auditLabel.TextSize = 10
+	auditLabel.TextStyle = fyne.TextStyle{Bold: true}
+
+	var apps []XSWDApp
+	var audit []AuditEntry
+	selected := ""
+
+	btnRevoke := widget.NewButton("REV", nil)
+	btnRevoke.Disable()
+
+	appList := widget.NewList(
+		func() int {
+			return len(apps)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(apps) {
+				return
+			}
+
+			app := apps[i]
+			seen := "never"
+			if !app.LastSeen.IsZero() {
+				seen = app.LastSeen.Format("2006-01-02 15:04")
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%-20.20s %-16s %s", app.Name, seen, app.URL)
+			line.Refresh()
+		},
+	)
+	appList.OnSelected = func(i widget.ListItemID) {
+		if i < len(apps) {
+			selected = apps[i].ID
+			btnRevoke.Enable()
+		}
+	}
+
+	btnRevoke.OnTapped = func() {
+		if selected == "" {
+			return
+		}
+
+		dialog.ShowConfirm("Revoke Application", "Forget this application and every permission it was given?", func(ok bool) {
+			if !ok {
+				return
+			}
+
+			gateway.revoke(selected)
+			selected = ""
+			btnRevoke.Disable()
+			appList.UnselectAll()
+			apps = gateway.registry()
+			appList.Refresh()
+		}, a.window)
+	}
+
+	auditList := widget.NewList(
+		func() int {
+			return len(audit)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(audit) {
+				return
+			}
+
+			e := audit[i]
+			method := e.Method
+			if method == "" {
+				method = "connect"
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%s %-16.16s %-22.22s %s", e.Time.Format("15:04:05"), e.App, method, e.Decision)
+			line.Color = colors.white
+			if e.Error != "" || e.Decision == "refused" || strings.Contains(e.Decision, "deny") {
+				line.Color = colors.gray
+			}
+			line.Refresh()
+		},
+	)
+
+	// Requests wait on the user, the window is brought up if it was hidden to the tray
+	gateway.setPrompt(func(req PermissionRequest) Permission {
+		answer := make(chan Permission, 1)
+
+		title := "Connect Application"
+		text := fmt.Sprintf("%s wants to connect to your wallet.\n\n%s\n%s", req.app.Name, req.app.URL, req.app.Description)
+		if !req.verified {
+			text += "\n\nThe application is not signed, its name, url and origin are not proof of who it is. It will be asked for again on every connection."
+		}
+		if req.method != "" {
+			title = "Application Request"
+			text = fmt.Sprintf("%s (%s) requests %s", req.app.Name, req.app.URL, req.method)
+		}
+
+		message := widget.NewLabel(text)
+		message.Wrapping = fyne.TextWrapWord
+
+		// The params are shown in full, a long request scrolls
+		body := container.NewVBox(message)
+		if req.params != "" {
+			params := widget.NewLabel(req.params)
+			params.Wrapping = fyne.TextWrapBreak
+			params.TextStyle = fyne.TextStyle{Monospace: true}
+
+			scroll := container.NewVScroll(params)
+			scroll.SetMinSize(fyne.NewSize(0, 240))
+			body.Add(scroll)
+		}
+
+		var d dialog.Dialog
+		reply := func(p Permission) func() {
+			return func() {
+				answer <- p
+				d.Hide()
+			}
+		}
+
+		buttons := container.NewHBox(
+			layout.NewSpacer(),
+			widget.NewButton("Deny", reply(PERMISSION_DENY)),
+			widget.NewButton("Allow", reply(PERMISSION_ALLOW)),
+		)
+		if req.always {
+			buttons.Add(widget.NewButton("Always deny", reply(PERMISSION_ALWAYS_DENY)))
+			buttons.Add(widget.NewButton("Always allow", reply(PERMISSION_ALWAYS_ALLOW)))
+		}
+
+		rect := canvas.NewRectangle(color.Transparent)
+		rect.SetMinSize(fyne.NewSize(460, 0))
+
+		d = dialog.NewCustomWithoutButtons(title, container.NewVBox(rect, body, buttons), a.window)
+
+		if status.hidden {
+			toggleWindow()
+		}
+		d.Show()
+
+		select {
+		case p := <-answer:
+			return p
+		case <-time.After(XSWD_PROMPT_TIMEOUT):
+			d.Hide()
+			return PERMISSION_DENY
+		}
+	})
+
+	btnOpen := widget.NewButton("OPN", nil)
+	btnClose := widget.NewButton("CLS", nil)
+	btnClose.Disable()
+
+	update := func() {
+		apps = gateway.registry()
+		appList.Refresh()
+
+		audit = gateway.auditLog()
+		for i, j := 0, len(audit)-1; i < j; i, j = i+1, j-1 {
+			audit[i], audit[j] = audit[j], audit[i]
+		}
+		auditList.Refresh()
+
+		scanned := 0
+		if addr, err := miningAddress(); err == nil {
+			address.Text = addr.String()
+			rewards, scanned = analytics.rewards(addr)
+		} else {
+			address.Text = "---"
+			rewards = nil
+		}
+		address.Refresh()
+
+		total := uint64(0)
+		minis := 0
+		for _, r := range rewards {
+			total += r.amount
+			minis += r.miniblocks
+		}
+
+		if bw.chain != nil {
+			set(earned, globals.FormatMoney(total)+" DERO", fmt.Sprintf("%d miniblocks in the last %d blocks", minis, scanned))
+		} else {
+			set(earned, "---", "daemon offline")
+		}
+
+		if len(rewards) > REWARDS_SHOWN {
+			rewards = rewards[:REWARDS_SHOWN]
+		}
+		rewardList.Refresh()
+
+		if w := dappWallet.get(); w != nil {
+			dappAddress.Text = w.GetAddress().String()
+			btnDappOpen.Disable()
+			btnDappClose.Enable()
+		} else {
+			dappAddress.Text = "---"
+			btnDappOpen.Enable()
+			btnDappClose.Disable()
+		}
+		dappAddress.Refresh()
+
+		mature, lock, height, registered, online, list, ok := miningWallet.state()
+		if !ok {
+			set(balance, "---", "")
+			set(locked, "---", "")
+			set(wstatus, "Closed", "")
+			transfers = nil
+			transferList.Refresh()
+			btnOpen.Enable()
+			btnClose.Disable()
+			return
+		}
+
+		set(balance, globals.FormatMoney(mature)+" DERO", "")
+		set(locked, globals.FormatMoney(lock)+" DERO", "")
+
+		state := "Offline"
+		if online {
+			state = "Online"
+		}
+		note := fmt.Sprintf("synced to %d", height)
+		if !registered {
+			note = "not registered"
+		}
+		set(wstatus, state, note)
+
+		if len(list) > REWARDS_SHOWN {
+			list = list[:REWARDS_SHOWN]
+		}
+		transfers = list
+		transferList.Refresh()
+		btnOpen.Disable()
+		btnClose.Enable()
+	}
+
+	openWallet := func(title string, open func(file string, password string) error) func() {
+		return func() {
+			dialog.ShowFileOpen(func(r fyne.URIReadCloser, err error) {
+				if err != nil || r == nil {
+					return
+				}
+				name := r.URI().Path()
+				r.Close()
+
+				password := widget.NewPasswordEntry()
+				dialog.ShowForm(title, "Open", "Cancel", []*widget.FormItem{widget.NewFormItem("Password", password)}, func(ok bool) {
+					if !ok {
+						return
+					}
+
+					progress := dialog.NewCustomWithoutButtons("Opening Wallet", widget.NewProgressBarInfinite(), a.window)
+					progress.Show()
+
+					go func() {
+						err := open(name, password.Text)
+						progress.Hide()
+						if err != nil {
+							globals.Logger.Error(err, "[Netrunner] Could not open wallet")
+							dialog.ShowError(err, a.window)
+							return
+						}
+						update()
+					}()
+				}, a.window)
+			}, a.window)
+		}
+	}
+
+	btnOpen.OnTapped = openWallet("Open Mining Wallet", openMiningWallet)
+	btnDappOpen.OnTapped = openWallet("Open dApp Wallet", openDappWallet)
+
+	btnClose.OnTapped = func() {
+		btnClose.Disable()
+		go func() {
+			miningWallet.close()
+			update()
+		}()
+	}
+
+	btnDappClose.OnTapped = func() {
+		btnDappClose.Disable()
+		go func() {
+			closeDappWallet()
+			update()
+		}()
+	}
+
+	btnReturn := widget.NewButton("RTN", nil)
+	btnReturn.OnTapped = back
+
+	top := container.NewVBox(
+		container.NewHBox(
+			rectSpacer,
+			rect50,
+			walletTitle,
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				btnOpen,
+			),
+			rectSpacer,
+			container.NewMax(
+				btnRect,
+				btnClose,
+			),
+			rectSpacer,
+			container.NewMax(
+				btnRect,
+				btnReturn,
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+		div,
+	)
+
+	panel := container.NewBorder(
+		top,
+		nil,
+		rectSpacer,
+		rectSpacer,
+		container.NewVScroll(container.NewVBox(
+			rectSpacer,
+			addressLabel,
+			address,
+			walletInfo,
+			grid,
+			rectSpacer,
+			container.NewGridWithColumns(2,
+				container.NewVBox(
+					rewardsLabel,
+					container.NewMax(
+						rectList,
+						rewardList,
+					),
+				),
+				container.NewVBox(
+					transfersLabel,
+					container.NewMax(
+						rectList,
+						transferList,
+					),
+				),
+			),
+			rectSpacer,
+			gatewayLabel,
+			checkGateway,
+			gatewayInfo,
+			rectSpacer,
+			container.NewHBox(
+				dappLabel,
+				layout.NewSpacer(),
+				btnDappOpen,
+				btnDappClose,
+			),
+			dappAddress,
+			rectSpacer,
+			container.NewGridWithColumns(2,
+				container.NewVBox(
+					container.NewHBox(
+						appsLabel,
+						layout.NewSpacer(),
+						btnRevoke,
+					),
+					container.NewMax(
+						rectList,
+						appList,
+					),
+				),
+				container.NewVBox(
+					auditLabel,
+					container.NewMax(
+						rectList,
+						auditList,
+					),
+				),
+			),
+		)),
+	)
+
+	go func() {
+		for {
+			time.Sleep(time.Second)
+			if panel.Visible() {
+				update()
+			}
+		}
+	}()
+
+	return panel
+}
+
+func layoutContracts(back func()) fyne.CanvasObject {
+	rect50 := canvas.NewRectangle(color.Transparent)
+	rect50.SetMinSize(fyne.NewSize(0, 50))
+
+	btnRect := canvas.NewRectangle(color.Transparent)
+	btnRect.SetMinSize(fyne.NewSize(110, 50))
+
+	div := canvas.NewRectangle(colors.gray)
+	div.SetMinSize(fyne.NewSize(0, 1))
+
+	rectSpacer := canvas.NewRectangle(color.Transparent)
+	rectSpacer.SetMinSize(fyne.NewSize(10, 5))
+
+	rectList := canvas.NewRectangle(color.Transparent)
+	rectList.SetMinSize(fyne.NewSize(0, 200))
+
+	rectSource := canvas.NewRectangle(color.Transparent)
+	rectSource.SetMinSize(fyne.NewSize(0, 300))
+
+	contractsTitle := canvas.NewText("Contracts", colors.red)
+	contractsTitle.TextStyle = fyne.TextStyle{Bold: true}
+	contractsTitle.TextSize = 25
+
+	newLabel := func(text string) *canvas.Text {
+		label := canvas.NewText(text, colors.red)
+		label.TextSize = 10
+		label.TextStyle = fyne.TextStyle{Bold: true}
+		return label
+	}
+
+	scidLabel := newLabel("SCID")
+	sourceLabel := newLabel("SOURCE")
+	variablesLabel := newLabel("VARIABLES")
+	balancesLabel := newLabel("BALANCES")
+	invokeLabel := newLabel("INVOKE")
+	entryLabel := newLabel("ENTRYPOINT")
+	depositLabel := newLabel("DEPOSIT  DERO")
+	argsLabel := newLabel("ARGUMENTS")
+
+	scid := widget.NewEntry()
+	scid.SetPlaceHolder("64 hex characters")
+
+	info := canvas.NewText("Load a contract from the local chain to read its state and call it", colors.gray)
+	info.TextSize = 12
+
+	result := canvas.NewText("", colors.gray)
+	result.TextSize = 12
+
+	var contract Contract
+	var source []string
+
+	newLine := func() fyne.CanvasObject {
+		line := canvas.NewText("", colors.white)
+		line.TextSize = 11
+		line.TextStyle = fyne.TextStyle{Monospace: true}
+		return line
+	}
+
+	sourceList := widget.NewList(
+		func() int {
+			return len(source)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(source) {
+				return
+			}
+
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%4d  %s", i+1, strings.ReplaceAll(source[i], "\t", "    "))
+			line.Refresh()
+		},
+	)
+
+	variableList := widget.NewList(
+		func() int {
+			return len(contract.variables)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(contract.variables) {
+				return
+			}
+
+			v := contract.variables[i]
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%-24s %s", v.key, v.value)
+			line.Refresh()
+		},
+	)
+
+	balanceList := widget.NewList(
+		func() int {
+			return len(contract.balances)
+		},
+		newLine,
+		func(i widget.ListItemID, o fyne.CanvasObject) {
+			if i >= len(contract.balances) {
+				return
+			}
+
+			b := contract.balances[i]
+			line := o.(*canvas.Text)
+			line.Text = fmt.Sprintf("%-18.16s %s", b.name(), globals.FormatMoney(b.amount))
+			line.Refresh()
+		},
+	)
+
+	deposit := widget.NewEntry()
+	deposit.SetPlaceHolder("0")
+
+	argsBox := container.NewVBox()
+	var argEntries []*widget.Entry
+
+	selEntry := widget.NewSelect(nil, nil)
+	selEntry.PlaceHolder = "Load a contract"
+
+	function := func() (ContractFunction, bool) {
+		for _, f := range contract.functions {
+			if f.name == selEntry.Selected {
+				return f, true
+			}
+		}
+		return ContractFunction{}, false
+	}
+
+	// The last dry run, sending is only offered for exactly what was estimated
+	var estimated derorpc.Arguments
+	var estimatedDeposit, estimatedGas uint64
+	var estimatedSigner string
+
+	btnSend := widget.NewButton("SND", nil)
+	btnSend.Disable()
+
+	invalidate := func() {
+		estimated = nil
+		btnSend.Disable()
+	}
+
+	selEntry.OnChanged = func(s string) {
+		invalidate()
+		argsBox.RemoveAll()
+		argEntries = nil
+
+		f, ok := function()
+		if !ok {
+			return
+		}
+
+		for _, p := range f.params {
+			e := widget.NewEntry()
+			kind := "String"
+			if p.kind == derorpc.DataUint64 {
+				kind = "Uint64"
+			}
+			e.SetPlaceHolder(fmt.Sprintf("%s  %s", p.name, kind))
+			e.OnChanged = func(string) { invalidate() }
+			argEntries = append(argEntries, e)
+			argsBox.Add(e)
+		}
+
+		if len(f.params) == 0 {
+			none := canvas.NewText("No arguments", colors.gray)
+			none.TextSize = 12
+			argsBox.Add(none)
+		}
+		argsBox.Refresh()
+	}
+	deposit.OnChanged = func(string) { invalidate() }
+
+	show := func(text string, c color.Color) {
+		result.Text = text
+		result.Color = c
+		result.Refresh()
+	}
+
+	btnLoad := widget.NewButton("LOD", nil)
+	btnLoad.OnTapped = func() {
+		c, err := loadContract(scid.Text)
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+
+		contract = c
+		source = strings.Split(strings.ReplaceAll(c.code, "\r\n", "\n"), "\n")
+
+		info.Text = fmt.Sprintf("%d functions, %d variables, balance %s DERO", len(c.functions), len(c.variables), globals.FormatMoney(c.balance))
+		info.Refresh()
+
+		var names []string
+		for _, f := range c.functions {
+			names = append(names, f.name)
+		}
+		selEntry.Options = names
+		selEntry.PlaceHolder = "Select a function"
+		selEntry.ClearSelected()
+
+		show("", colors.gray)
+		sourceList.Refresh()
+		variableList.Refresh()
+		balanceList.Refresh()
+	}
+
+	btnEstimate := widget.NewButton("EST", nil)
+	btnEstimate.OnTapped = func() {
+		invalidate()
+
+		f, ok := function()
+		if !ok || contract.scid == "" {
+			dialog.ShowError(fmt.Errorf("Load a contract and select a function first"), a.window)
+			return
+		}
+
+		var values []string
+		for _, e := range argEntries {
+			values = append(values, e.Text)
+		}
+
+		args, err := invokeArguments(contract.scid, f, values)
+		if err != nil {
+			dialog.ShowError(err, a.window)
+			return
+		}
+
+		amount := uint64(0)
+		if strings.TrimSpace(deposit.Text) != "" {
+			if amount, err = globals.ParseAmount(strings.TrimSpace(deposit.Text)); err != nil {
+				dialog.ShowError(fmt.Errorf("Deposit is not a valid DERO amount"), a.window)
+				return
+			}
+		}
+
+		gas, signer, err := estimateInvoke(args, amount)
+		if err != nil {
+			show("Dry run failed: "+err.Error(), colors.red)
+			return
+		}
+
+		estimated, estimatedDeposit, estimatedGas, estimatedSigner = args, amount, gas.GasStorage, signer
+		show(fmt.Sprintf("Dry run OK, gas compute %d, gas storage %d", gas.GasCompute, gas.GasStorage), colors.green)
+		btnSend.Enable()
+	}
+
+	btnSend.OnTapped = func() {
+		if estimated == nil {
+			return
+		}
+
+		args, amount, gas, signer := estimated, estimatedDeposit, estimatedGas, estimatedSigner
+		text := fmt.Sprintf("Call %s on %.16s... from the dApp wallet?\n\nDeposit %s DERO, gas storage %d", args.Value("entrypoint", derorpc.DataString), contract.scid, globals.FormatMoney(amount), gas)
+
+		dialog.ShowConfirm("Invoke Contract", text, func(ok bool) {
+			if !ok {
+				return
+			}
+
+			btnSend.Disable()
+			go func() {
+				txid, err := sendInvoke(args, amount, gas, signer)
+				if err != nil {
+					globals.Logger.Error(err, "[Netrunner] Smart contract call failed")
+					show("Send failed: "+err.Error(), colors.red)
+					btnSend.Enable()
+					return
+				}
+				invalidate()
+				show("Sent "+txid, colors.green)
+			}()
+		}, a.window)
+	}
+
+	btnReturn := widget.NewButton("RTN", nil)
+	btnReturn.OnTapped = back
+
+	top := container.NewVBox(
+		container.NewHBox(
+			rectSpacer,
+			rect50,
+			contractsTitle,
+			layout.NewSpacer(),
+			container.NewMax(
+				btnRect,
+				btnReturn,
+			),
+			rectSpacer,
+		),
+		rectSpacer,
+		div,
+	)
+
+	panel := container.NewBorder(
+		top,
+		nil,
+		rectSpacer,
+		rectSpacer,
+		container.NewVScroll(container.NewVBox(
+			rectSpacer,
+			scidLabel,
+			container.NewBorder(
+				nil,
+				nil,
+				nil,
+				container.NewMax(
+					btnRect,
+					btnLoad,
+				),
+				scid,
+			),
+			info,
+			rectSpacer,
+			sourceLabel,
+			container.NewMax(
+				rectSource,
+				sourceList,
+			),
+			rectSpacer,
+			container.NewGridWithColumns(2,
+				container.NewVBox(
+					variablesLabel,
+					container.NewMax(
+						rectList,
+						variableList,
+					),
+				),
+				container.NewVBox(
+					balancesLabel,
+					container.NewMax(
+						rectList,
+						balanceList,
+					),
+				),
+			),
+			rectSpacer,
+			invokeLabel,
+			container.NewGridWithColumns(2,
+				container.NewVBox(
+					entryLabel,
+					selEntry,
+				),
+				container.NewVBox(
+					depositLabel,
+					deposit,
+				),
+			),
+			argsLabel,
+			argsBox,
+			rectSpacer,
+			container.NewHBox(
+				container.NewMax(
+					btnRect,
+					btnEstimate,
+				),
+				rectSpacer,
+				container.NewMax(
+					btnRect,
+					btnSend,
+				),
+				rectSpacer,
+				result,
+			),
+			rectSpacer,
+		)),
+	)
+
+	return panel
 }
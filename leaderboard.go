@@ -0,0 +1,204 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"encoding/hex"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/config"
+	"github.com/deroproject/graviton"
+)
+
+// Miniblocks found by one miner key over a period. Miniblocks carry just a hash of the key,
+// the address is looked up in the balance tree
+type MinerRank struct {
+	key        [16]byte
+	address    string
+	miniblocks int
+	final      int // blocks it integrated
+	share      float64
+	ours       bool
+}
+
+var leaderboardPeriods = map[string]time.Duration{
+	"Hour": time.Hour,
+	"Day":  24 * time.Hour,
+}
+
+// A registered key stays in the balance tree, a hash resolved once is kept
+var minerKeys = struct {
+	sync.Mutex
+	keys map[[16]byte][33]byte
+}{keys: map[[16]byte][33]byte{}}
+
+func keyHash(key [33]byte) (hash [16]byte) {
+	sum := graviton.Sum(key[:])
+	copy(hash[:], sum[:16])
+
+	return
+}
+
+// Count miniblocks per key in samples from since (milliseconds) on, most first
+func minerRanks(samples []BlockSample, since uint64, ours [16]byte) (ranks []MinerRank, total int) {
+	index := map[[16]byte]int{}
+	integrators := map[[16]byte][33]byte{}
+
+	count := func(key [16]byte, final bool) {
+		i, ok := index[key]
+		if !ok {
+			i = len(ranks)
+			index[key] = i
+			ranks = append(ranks, MinerRank{key: key, ours: key == ours})
+		}
+
+		ranks[i].miniblocks++
+		if final {
+			ranks[i].final++
+		}
+		total++
+	}
+
+	for _, s := range samples {
+		if s.timestamp < since || s.miniblocks < 1 {
+			continue
+		}
+
+		for _, k := range s.keys {
+			count(k, false)
+		}
+
+		hash := keyHash(s.integrator)
+		integrators[hash] = s.integrator
+		count(hash, true)
+	}
+
+	for i := range ranks {
+		if key, ok := integrators[ranks[i].key]; ok {
+			ranks[i].address, _ = integratorAddress(key)
+		}
+		if total > 0 {
+			ranks[i].share = float64(ranks[i].miniblocks) / float64(total) * 100
+		}
+	}
+
+	sort.SliceStable(ranks, func(i, j int) bool {
+		if ranks[i].miniblocks != ranks[j].miniblocks {
+			return ranks[i].miniblocks > ranks[j].miniblocks
+		}
+		return hex.EncodeToString(ranks[i].key[:]) < hex.EncodeToString(ranks[j].key[:])
+	})
+
+	return
+}
+
+// Leaderboard over the newest period of the sampled history, covered is how much of
+// the period the history reaches back to
+func (a *Analytics) leaderboard(period time.Duration, ours [16]byte) (ranks []MinerRank, total int, blocks int, covered time.Duration) {
+	a.Lock()
+	defer a.Unlock()
+
+	if len(a.samples) == 0 {
+		return
+	}
+
+	newest := a.samples[len(a.samples)-1].timestamp
+	since := uint64(0)
+	if ms := uint64(period.Milliseconds()); newest > ms {
+		since = newest - ms
+	}
+
+	for _, s := range a.samples {
+		if s.timestamp >= since {
+			blocks++
+		}
+	}
+
+	covered = time.Duration(newest-a.samples[0].timestamp) * time.Millisecond
+	if covered > period {
+		covered = period
+	}
+
+	ranks, total = minerRanks(a.samples, since, ours)
+
+	return
+}
+
+// Fill in the addresses of keys that only mined miniblocks from the balance tree at the
+// top of the chain, as derod names the miners of a block
+func resolveMiners(chain *blockchain.Blockchain, ranks []MinerRank) {
+	minerKeys.Lock()
+	defer minerKeys.Unlock()
+
+	var tree *graviton.Tree
+	for i := range ranks {
+		if ranks[i].address != "" {
+			continue
+		}
+
+		key, ok := minerKeys.keys[ranks[i].key]
+		if !ok {
+			if tree == nil {
+				if tree = balanceTree(chain); tree == nil {
+					return
+				}
+			}
+
+			// Fewer bits means another key shares the hash prefix, derod skips those too
+			bits, compressed, _, err := tree.GetKeyValueFromHash(ranks[i].key[:])
+			if err != nil || bits >= 120 || len(compressed) != len(key) {
+				continue
+			}
+			copy(key[:], compressed)
+			minerKeys.keys[ranks[i].key] = key
+		}
+
+		ranks[i].address, _ = integratorAddress(key)
+	}
+}
+
+func balanceTree(chain *blockchain.Blockchain) *graviton.Tree {
+	if chain == nil {
+		return nil
+	}
+
+	record, err := chain.Store.Topo_store.Read(chain.Load_TOPO_HEIGHT())
+	if err != nil {
+		return nil
+	}
+
+	ss, err := chain.Store.Balance_store.LoadSnapshot(record.State_Version)
+	if err != nil {
+		return nil
+	}
+
+	tree, err := ss.GetTree(config.BALANCE_TREE)
+	if err != nil {
+		return nil
+	}
+
+	return tree
+}
+
+func (r MinerRank) name() string {
+	if r.address != "" {
+		return r.address
+	}
+
+	return "key " + hex.EncodeToString(r.key[:])
+}
@@ -0,0 +1,207 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/globals"
+)
+
+// A startup step, a fatal failure stops on the loading screen with the
+// option to retry, any other failure is reported once the main view is up
+type loadStep struct {
+	name  string
+	fatal bool
+	run   func() error
+}
+
+// Widgets of the loading screen updated as startup progresses
+type Loader struct {
+	step     *canvas.Text
+	message  *canvas.Text
+	progress *widget.ProgressBar
+	retry    *widget.Button
+	quit     *widget.Button
+}
+
+var loader Loader
+
+// Set once the main view is up, settings are not saved before they were loaded
+var started bool
+
+func startupSteps() []loadStep {
+	return []loadStep{
+		{"Loading configuration", false, loadConfig},
+		{"Loading resources", true, checkResources},
+		{"Initializing network", true, initNetwork},
+		{"Running preflight checks", false, startupPreflight},
+		{"Building interface", true, func() error {
+			a.window.SetContent(layoutMain())
+			return nil
+		}},
+		{"Starting daemon", false, startupDaemon},
+		{"Starting dApp gateway", false, startupGateway},
+	}
+}
+
+// Run the startup steps from first, the main view replaces the loading screen when done
+func startup(first int) {
+	steps := startupSteps()
+	var warnings []string
+
+	loader.retry.Hide()
+	loader.quit.Hide()
+	loader.message.Text = ""
+	loader.message.Refresh()
+	loader.progress.Max = float64(len(steps))
+
+	for i := first; i < len(steps); i++ {
+		s := steps[i]
+		loader.step.Text = s.name + "..."
+		loader.step.Refresh()
+		loader.progress.SetValue(float64(i))
+
+		if err := s.run(); err != nil {
+			globals.Logger.Error(err, "[Netrunner] Startup step failed", "step", s.name)
+
+			if s.fatal {
+				loader.step.Text = s.name + " failed"
+				loader.step.Refresh()
+				loader.message.Text = err.Error()
+				loader.message.Refresh()
+				loader.retry.OnTapped = func() {
+					go startup(i)
+				}
+				loader.retry.Show()
+				loader.quit.Show()
+				return
+			}
+
+			warnings = append(warnings, fmt.Sprintf("%s: %s", s.name, err))
+		}
+	}
+
+	loader.progress.SetValue(loader.progress.Max)
+	started = true
+	globals.Logger.Info("[Netrunner] Startup complete")
+
+	if len(warnings) > 0 {
+		dialog.ShowInformation("Startup Warnings", strings.Join(warnings, "\n"), a.window)
+	}
+}
+
+// Saved settings fill in anything not given on the command line
+func loadConfig() error {
+	err := loadSettings()
+
+	if globals.Arguments["--data-dir"] == nil && status.data_dir != "" {
+		globals.Arguments["--data-dir"] = status.data_dir
+	}
+
+	// --testnet on the command line wins over the network used last
+	if testnet, ok := globals.Arguments["--testnet"].(bool); ok && testnet && networkName() != NETWORK_TESTNET {
+		storeNetworkSettings()
+		status.network = true
+		status.simulator = false
+		loadNetworkSettings()
+	}
+
+	if globals.Arguments["--clog-level"] == nil && status.clog_level != "" {
+		globals.Arguments["--clog-level"] = status.clog_level
+	}
+
+	if globals.Arguments["--flog-level"] == nil && status.flog_level != "" {
+		globals.Arguments["--flog-level"] = status.flog_level
+	}
+
+	if err != nil {
+		return fmt.Errorf("settings could not be read, defaults are used: %w", err)
+	}
+
+	return nil
+}
+
+func checkResources() error {
+	for _, r := range []fyne.Resource{resourceNetrunnerPng, resourceLoadPng, resourceDaemonOnPng, resourceDaemonOffPng, resourceMinerOnPng, resourceMinerOffPng, resourceIconPng} {
+		if r == nil || len(r.Content()) == 0 {
+			return fmt.Errorf("bundled resources are missing, the build is incomplete")
+		}
+	}
+
+	loadResources()
+
+	return nil
+}
+
+// The network used last with fast sync until the user picks otherwise
+func initNetwork() error {
+	status.fastsync = true
+	globals.Arguments["--fastsync"] = true
+	initGlobals()
+
+	dir := globals.GetDataDirectory()
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return fmt.Errorf("data directory %s is not usable: %w", dir, err)
+	}
+
+	return nil
+}
+
+func startupDaemon() error {
+	resume := takeResume()
+	resumeMiner = resume == RESUME_MINER
+
+	if (!status.auto_start && resume == "") || runDaemon == nil {
+		return nil
+	}
+
+	globals.Logger.Info("[Netrunner] Starting daemon on launch")
+	runDaemon()
+
+	if bw.chain == nil {
+		return fmt.Errorf("daemon did not start, see the log for details")
+	}
+
+	return nil
+}
+
+// Problems that would stop the daemon from starting, reported early
+func startupPreflight() error {
+	dir := globals.GetDataDirectory()
+
+	if err := checkLock(dir); err != nil {
+		return err
+	}
+
+	if free, err := diskFree(dir); err == nil && free < DISK_STOP_BYTES {
+		return fmt.Errorf("only %s free on the data directory volume", blockchain.ByteCountIEC(int64(free)))
+	}
+
+	bind, err := rpcBindAddress(status.rpc_mode, status.rpc_bind, rpcPort())
+	if err != nil {
+		return err
+	}
+
+	return preflight(bind)
+}
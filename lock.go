@@ -0,0 +1,259 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/deroproject/derohe/globals"
+)
+
+// Written into the data directory while Netrunner owns it, so a second instance
+// or another derod cannot open the same chain database
+type InstanceLock struct {
+	PID      int       `json:"pid"`
+	Hostname string    `json:"hostname"`
+	Started  time.Time `json:"started"`
+	path     string
+}
+
+// A port needed by the daemon is already taken
+type PortConflict struct {
+	service string
+	network string
+	address string
+	pid     int
+	process string
+}
+
+const LOCK_FILE = "netrunner.lock"
+
+var instance *InstanceLock
+
+func (e *PortConflict) Error() string {
+	owner := "another application"
+	if e.pid > 0 {
+		owner = fmt.Sprintf("process %d", e.pid)
+		if e.process != "" {
+			owner = fmt.Sprintf("%s (pid %d)", e.process, e.pid)
+		}
+	}
+
+	return fmt.Sprintf("%s port %s is already in use by %s.\nStop it or choose another port.", e.service, e.address, owner)
+}
+
+// Take the lock on dir, a lock left by a process that is no longer running is replaced
+func acquireLock(dir string) error {
+	if instance != nil && instance.path == filepath.Join(dir, LOCK_FILE) {
+		return nil
+	}
+
+	if err := os.MkdirAll(dir, 0750); err != nil {
+		return err
+	}
+
+	path := filepath.Join(dir, LOCK_FILE)
+	hostname, _ := os.Hostname()
+	lock := &InstanceLock{PID: os.Getpid(), Hostname: hostname, Started: time.Now().UTC(), path: path}
+
+	data, err := json.Marshal(lock)
+	if err != nil {
+		return err
+	}
+
+	for retry := 0; retry < 2; retry++ {
+		file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err == nil {
+			_, err = file.Write(data)
+			file.Close()
+			if err != nil {
+				os.Remove(path)
+				return err
+			}
+
+			instance = lock
+			globals.Logger.V(1).Info("[Netrunner] Data directory locked", "path", path)
+			return nil
+		}
+
+		if !os.IsExist(err) {
+			return err
+		}
+
+		held, err := readLock(path)
+		if err == nil && !held.stale() {
+			return fmt.Errorf("%s is in use by another Netrunner (pid %d on %s, started %s)", dir, held.PID, held.Hostname, held.Started.Local().Format("2006-01-02 15:04"))
+		}
+
+		globals.Logger.Info("[Netrunner] Removing stale data directory lock", "path", path)
+		if err = os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return fmt.Errorf("could not lock %s", dir)
+}
+
+// Refuse to touch dir while another instance holds it, without taking the lock
+func checkLock(dir string) error {
+	held, err := readLock(filepath.Join(dir, LOCK_FILE))
+	if err != nil || held.stale() {
+		return nil
+	}
+
+	return fmt.Errorf("%s is in use by another Netrunner (pid %d on %s)", dir, held.PID, held.Hostname)
+}
+
+func releaseLock() {
+	if instance == nil {
+		return
+	}
+
+	if held, err := readLock(instance.path); err == nil && held.PID == instance.PID {
+		os.Remove(instance.path)
+	}
+
+	globals.Logger.V(1).Info("[Netrunner] Data directory unlocked", "path", instance.path)
+	instance = nil
+}
+
+func readLock(path string) (lock InstanceLock, err error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return
+	}
+
+	err = json.Unmarshal(data, &lock)
+
+	return
+}
+
+// The pid may have been reused after a crash, so a live process must also look like us
+func (l InstanceLock) stale() bool {
+	hostname, _ := os.Hostname()
+	if l.Hostname != hostname {
+		// Can't check processes on another machine sharing the directory
+		return false
+	}
+
+	if l.PID == os.Getpid() || !processAlive(l.PID) {
+		return true
+	}
+
+	name := processName(l.PID)
+	if name == "" {
+		return false
+	}
+
+	self, err := os.Executable()
+	if err != nil {
+		return false
+	}
+
+	return !sameProgram(name, filepath.Base(self))
+}
+
+// Linux keeps only this many bytes of a process name in comm
+const COMM_LENGTH = 15
+
+func sameProgram(name string, self string) bool {
+	name = strings.TrimSuffix(name, ".exe")
+	self = strings.TrimSuffix(self, ".exe")
+	if len(name) == COMM_LENGTH && len(self) > COMM_LENGTH {
+		self = self[:COMM_LENGTH]
+	}
+
+	return strings.EqualFold(name, self)
+}
+
+// Check the ports the daemon is about to open before handing them to derod
+func preflight(rpc string) error {
+	if err := checkPort("RPC", "tcp", rpc); err != nil {
+		return err
+	}
+
+	// derod cannot stop its getwork server, after a run in this process it keeps serving the
+	// restarted chain, switchNetwork relaunches Netrunner so that is always the same network
+	if !bw.ran {
+		getwork := "0.0.0.0:" + strconv.Itoa(globals.Config.GETWORK_Default_Port)
+		if status.simulator {
+			getwork = simulatorGetwork()
+		} else if globals.Arguments["--getwork-bind"] != nil {
+			getwork = globals.Arguments["--getwork-bind"].(string)
+		}
+
+		if err := checkPort("Getwork", "tcp", getwork); err != nil {
+			return err
+		}
+	}
+
+	// P2P picks a free port itself unless one was set
+	if globals.Arguments["--p2p-bind"] != nil {
+		if err := checkPort("P2P", "udp", globals.Arguments["--p2p-bind"].(string)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkPort(service string, network string, address string) error {
+	_, p, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%s address %s is invalid", service, address)
+	}
+
+	port, err := strconv.Atoi(p)
+	if err != nil {
+		return fmt.Errorf("%s port %s is invalid", service, p)
+	}
+
+	// Port 0 disables the service
+	if port == 0 {
+		return nil
+	}
+
+	if network == "udp" {
+		conn, err := net.ListenPacket("udp", address)
+		if err == nil {
+			conn.Close()
+			return nil
+		}
+	} else {
+		l, err := net.Listen("tcp", address)
+		if err == nil {
+			l.Close()
+			return nil
+		}
+	}
+
+	conflict := &PortConflict{service: service, network: network, address: address}
+	if pid, err := portOwner(network, port); err == nil {
+		conflict.pid = pid
+		conflict.process = processName(pid)
+	}
+
+	globals.Logger.Error(nil, "[Netrunner] Port conflict", "service", service, "address", address, "pid", conflict.pid, "process", conflict.process)
+
+	return conflict
+}
@@ -0,0 +1,398 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/globals"
+	"github.com/go-logr/logr"
+	"go.uber.org/zap/zapcore"
+)
+
+// A single log record as shown in the log pane, level follows zap where
+// errors are 2, info is 0 and each verbosity step below that is one lower
+type LogEntry struct {
+	time   time.Time
+	level  int
+	name   string
+	msg    string
+	fields string
+}
+
+// Fixed size ring of the most recent log entries
+type LogBuffer struct {
+	sync.RWMutex
+	entries []LogEntry
+	next    int
+	full    bool
+}
+
+// Collects log records until the daemon sets up its own logger, records at or
+// above the console level are also written to console
+type logSink struct {
+	name    string
+	values  []interface{}
+	console io.Writer
+}
+
+const (
+	LOG_BUFFER_SIZE = 20000
+	LOG_TAIL_DELAY  = 500 * time.Millisecond
+	LOG_TIME_FORMAT = "2006-01-02 15:04:05.000"
+)
+
+// Level choices of the log pane mapped to the lowest zap level shown
+var logLevels = []string{"Error", "Info", "Debug", "Trace"}
+var logLevelValues = map[string]int{"Error": 2, "Info": 0, "Debug": -1, "Trace": -127}
+
+var logSubsystems = []string{"All", "Netrunner", "Miner", "Blockchain", "P2P", "RPC", "Getwork", "Pool", "Daemon"}
+
+var logs = LogBuffer{entries: make([]LogEntry, LOG_BUFFER_SIZE)}
+var logTail sync.Once
+
+func (b *LogBuffer) add(e LogEntry) {
+	b.Lock()
+	b.entries[b.next] = e
+	b.next = (b.next + 1) % len(b.entries)
+	if b.next == 0 {
+		b.full = true
+	}
+	b.Unlock()
+}
+
+// Entries in order that pass keep, oldest first
+func (b *LogBuffer) filter(keep func(e LogEntry) bool) (list []LogEntry) {
+	b.RLock()
+	defer b.RUnlock()
+
+	start := 0
+	if b.full {
+		start = b.next
+	}
+
+	for i := 0; i < len(b.entries); i++ {
+		e := b.entries[(start+i)%len(b.entries)]
+		if e.time.IsZero() {
+			continue
+		}
+
+		if keep(e) {
+			list = append(list, e)
+		}
+	}
+
+	return
+}
+
+// Netrunner and miner messages are logged unnamed with a bracket prefix
+func (e LogEntry) subsystem() string {
+	switch strings.ToUpper(strings.SplitN(e.name, ".", 2)[0]) {
+	case "CORE":
+		return "Blockchain"
+	case "P2P":
+		return "P2P"
+	case "RPC":
+		return "RPC"
+	case "GETWORK":
+		return "Getwork"
+	case "MEMPOOL", "REGPOOL":
+		return "Pool"
+	}
+
+	switch {
+	case strings.HasPrefix(e.msg, "[Netrunner]"):
+		return "Netrunner"
+	case strings.HasPrefix(e.msg, "[Miner]"):
+		return "Miner"
+	}
+
+	return "Daemon"
+}
+
+func (e LogEntry) levelString() string {
+	switch {
+	case e.level >= 2:
+		return "ERROR"
+	case e.level == 1:
+		return "WARN"
+	case e.level == 0:
+		return "INFO"
+	case e.level == -1:
+		return "DEBUG"
+	}
+
+	return fmt.Sprintf("V(%d)", -e.level)
+}
+
+func (e LogEntry) String() string {
+	s := fmt.Sprintf("%s  %-6s %-10s %s", e.time.Local().Format(LOG_TIME_FORMAT), e.levelString(), e.subsystem(), e.msg)
+	if e.fields != "" {
+		s += "  " + e.fields
+	}
+
+	return s
+}
+
+// Key and value pairs as k=v in the order given
+func formatFields(kv []interface{}) string {
+	var parts []string
+	for i := 0; i+1 < len(kv); i += 2 {
+		parts = append(parts, fmt.Sprintf("%v=%v", kv[i], kv[i+1]))
+	}
+
+	if len(kv)%2 == 1 {
+		parts = append(parts, fmt.Sprintf("%v", kv[len(kv)-1]))
+	}
+
+	return strings.Join(parts, " ")
+}
+
+func (s *logSink) Init(info logr.RuntimeInfo) {}
+
+func (s *logSink) Enabled(level int) bool {
+	return true
+}
+
+func (s *logSink) add(e LogEntry) {
+	logs.add(e)
+
+	if s.console != nil && globals.Log_Level_Console.Enabled(zapcore.Level(e.level)) {
+		fmt.Fprintln(s.console, e.String())
+	}
+}
+
+func (s *logSink) Info(level int, msg string, kv ...interface{}) {
+	s.add(LogEntry{time: time.Now(), level: -level, name: s.name, msg: msg, fields: formatFields(append(s.values, kv...))})
+}
+
+func (s *logSink) Error(err error, msg string, kv ...interface{}) {
+	if err != nil {
+		kv = append([]interface{}{"error", err}, kv...)
+	}
+
+	s.add(LogEntry{time: time.Now(), level: 2, name: s.name, msg: msg, fields: formatFields(append(s.values, kv...))})
+}
+
+func (s *logSink) WithValues(kv ...interface{}) logr.LogSink {
+	return &logSink{name: s.name, values: append(append([]interface{}{}, s.values...), kv...), console: s.console}
+}
+
+func (s *logSink) WithName(name string) logr.LogSink {
+	if s.name != "" {
+		name = s.name + "." + name
+	}
+
+	return &logSink{name: name, values: s.values, console: s.console}
+}
+
+// The daemon log file derod writes next to the executable
+func daemonLogPath() string {
+	network := "testnet"
+	if globals.IsMainnet() {
+		network = "mainnet"
+	}
+
+	exename, _ := os.Executable()
+
+	return exename + "_daemon_" + network + ".log"
+}
+
+// derod replaces globals.Logger with its own console and file logger and its
+// subsystems keep copies of it, so once it runs the log pane follows its file
+func followDaemonLog() {
+	logTail.Do(func() {
+		go tailLog()
+	})
+}
+
+func tailLog() {
+	var file *os.File
+	var reader *bufio.Reader
+	var path string
+	var offset int64
+	var partial string
+	rotated := false
+
+	for {
+		if p := daemonLogPath(); p != path || file == nil {
+			if file != nil {
+				file.Close()
+				file = nil
+			}
+
+			f, err := os.Open(p)
+			if err != nil {
+				time.Sleep(LOG_TAIL_DELAY)
+				continue
+			}
+
+			// Only what is logged from now on, earlier sessions are in the file
+			whence := io.SeekEnd
+			if rotated {
+				whence = io.SeekStart
+			}
+
+			if offset, err = f.Seek(0, whence); err != nil {
+				f.Close()
+				time.Sleep(LOG_TAIL_DELAY)
+				continue
+			}
+
+			file = f
+			path = p
+			reader = bufio.NewReader(file)
+			partial = ""
+			rotated = false
+		}
+
+		// The file was rotated or truncated, reopen it from the start
+		if info, err := os.Stat(path); err == nil && info.Size() < offset {
+			file.Close()
+			file = nil
+			rotated = true
+			continue
+		}
+
+		for {
+			line, err := reader.ReadString('\n')
+			offset += int64(len(line))
+			if err != nil {
+				partial += line
+				break
+			}
+
+			if e, ok := parseLogLine(partial + line); ok {
+				logs.add(e)
+			}
+			partial = ""
+		}
+
+		time.Sleep(LOG_TAIL_DELAY)
+	}
+}
+
+// Parse a record written by the zap JSON encoder derod uses for its file
+func parseLogLine(line string) (e LogEntry, ok bool) {
+	var record map[string]interface{}
+	if err := json.Unmarshal([]byte(line), &record); err != nil {
+		return
+	}
+
+	if t, ok := record["T"].(string); ok {
+		e.time, _ = time.Parse("2006-01-02T15:04:05.000Z0700", t)
+	}
+	if e.time.IsZero() {
+		e.time = time.Now()
+	}
+
+	level, _ := record["L"].(string)
+	e.level = parseLevel(level)
+	e.name, _ = record["N"].(string)
+	e.msg, _ = record["M"].(string)
+
+	var keys []string
+	for k := range record {
+		switch k {
+		case "T", "L", "N", "M", "C", "S":
+		default:
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var kv []interface{}
+	for _, k := range keys {
+		kv = append(kv, k, record[k])
+	}
+	e.fields = formatFields(kv)
+
+	return e, true
+}
+
+// zap writes verbosity levels below debug as LEVEL(-n)
+func parseLevel(s string) int {
+	if strings.HasPrefix(s, "LEVEL(") {
+		if n, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(s, "LEVEL("), ")")); err == nil {
+			return n
+		}
+	}
+
+	var l zapcore.Level
+	if err := l.UnmarshalText([]byte(strings.ToLower(s))); err != nil {
+		return 0
+	}
+
+	return int(l)
+}
+
+// Change the console and file log levels of a running daemon, they also apply on the next start
+func setLogLevels(console int, file int) {
+	console = clampLogLevel(console)
+	file = clampLogLevel(file)
+
+	argsLock.Lock()
+	replaceArguments(map[string]interface{}{"--clog-level": strconv.Itoa(console), "--flog-level": strconv.Itoa(file)})
+	argsLock.Unlock()
+	globals.Log_Level_Console.SetLevel(zapcore.Level(-console))
+	globals.Log_Level_File.SetLevel(zapcore.Level(-file))
+
+	status.clog_level = strconv.Itoa(console)
+	status.flog_level = strconv.Itoa(file)
+
+	globals.Logger.Info("[Netrunner] Log levels changed", "console", console, "file", file)
+}
+
+func clampLogLevel(n int) int {
+	if n < 0 {
+		return 0
+	}
+
+	if n > 127 {
+		return 127
+	}
+
+	return n
+}
+
+// Write everything logged in the last period to w for bug reports
+func exportLogs(w io.Writer, period time.Duration) (int, error) {
+	since := time.Now().Add(-period)
+	list := logs.filter(func(e LogEntry) bool {
+		return !e.time.Before(since)
+	})
+
+	out := bufio.NewWriter(w)
+	fmt.Fprintf(out, "Netrunner %s  %s/%s  exported %s  last %s\n\n", version.String(), runtime.GOOS, runtime.GOARCH, time.Now().Format(LOG_TIME_FORMAT), period)
+
+	for _, e := range list {
+		if _, err := fmt.Fprintln(out, e.String()); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(list), out.Flush()
+}
@@ -17,8 +17,8 @@ package main
 
 import (
 	"image/color"
+	"os"
 	"runtime"
-	"time"
 
 	"fyne.io/fyne/v2"
 	"fyne.io/fyne/v2/app"
@@ -26,6 +26,7 @@ import (
 	"github.com/deroproject/derohe/config"
 	"github.com/deroproject/derohe/globals"
 	"github.com/docopt/docopt-go"
+	"github.com/go-logr/logr"
 )
 
 type App struct {
@@ -41,9 +42,33 @@ type Status struct {
 	bootstrap       bool
 	integrator      string
 	ip_daemon       string
+	rpc_mode        string
+	rpc_bind        string
+	rpc_allow       string
+	rpc_user        string
+	rpc_pass        string
+	rpc_public      bool
+	data_dir        string
+	disk_free       uint64
+	clog_level      string
+	flog_level      string
+	threads         int
+	auto_start      bool
+	auto_mine       bool
+	at_login        bool
+	close_action    string
+	hidden          bool
+	metrics_disk    bool
+	watts           float64
+	kwh_price       float64
+	gateway         bool
+	paused          bool
 	network         bool
+	simulator       bool
+	networks        map[string]NetworkSettings
 	fastsync        bool
 	block_time      float32
+	net_hashrate    float64
 	difficulty      uint64
 	height          int64
 	last_height     int64
@@ -61,6 +86,7 @@ type Status struct {
 	offset_ntp      string
 	total_blocks    int64
 	supply          uint64
+	emission        EmissionStats
 	tx_pool         int
 	reg_pool        int
 	uptime          string
@@ -68,8 +94,11 @@ type Status struct {
 }
 
 const (
-	MIN_WIDTH  = 1100
-	MIN_HEIGHT = 600
+	// Window sizes, layouts wider than the window scroll
+	DEFAULT_WIDTH     = 1100
+	DEFAULT_HEIGHT    = 600
+	WINDOW_MIN_WIDTH  = 800
+	WINDOW_MIN_HEIGHT = 500
 )
 
 var a App
@@ -84,13 +113,21 @@ func main() {
 	// Turn off profiler
 	runtime.MemProfileRate = 0
 
+	// Keep log records for the log pane and console until the daemon starts its own logger
+	globals.Logger = logr.New(&logSink{console: os.Stdout})
+
 	// Initialize terminal arguments if any
 	globals.Arguments, err = docopt.ParseArgs(command_line, nil, config.Version.String())
 	if err != nil {
 		globals.Logger.Error(err, "Error while parsing options err: %s\n")
 	}
 
-	globals.Initialize()
+	// Headless chain export, no window is opened
+	if globals.Arguments["--export"] != nil {
+		// Rows may be written to stdout, keep log records off it
+		globals.Logger = logr.New(&logSink{console: os.Stderr})
+		os.Exit(exportCommand())
+	}
 
 	version = semver.MustParse("0.1.0")
 	a.app = app.New()
@@ -100,17 +137,15 @@ func main() {
 	a.window = a.app.NewWindow("Netrunner")
 	a.window.SetIcon(resourceIconPng)
 	a.window.SetMaster()
-	a.window.SetCloseIntercept(appClose)
+	a.window.SetCloseIntercept(closeWindow)
 	a.window.SetPadded(false)
 	a.window.CenterOnScreen()
-	a.window.Resize(fyne.NewSize(MIN_WIDTH, MIN_HEIGHT))
-	a.window.SetFixedSize(true)
+	a.window.Resize(fyne.NewSize(DEFAULT_WIDTH, DEFAULT_HEIGHT))
 
 	a.explorer = a.app.NewWindow("Explorer")
 	a.explorer.SetIcon(resourceIconPng)
 	a.explorer.SetPadded(false)
-	a.explorer.Resize(fyne.NewSize(MIN_WIDTH, MIN_HEIGHT))
-	a.explorer.SetFixedSize(true)
+	a.explorer.Resize(fyne.NewSize(DEFAULT_WIDTH, DEFAULT_HEIGHT))
 	a.explorer.CenterOnScreen()
 	a.explorer.SetContent(layoutExplorer())
 	a.explorer.Hide()
@@ -124,10 +159,11 @@ func main() {
 
 	a.window.SetContent(layoutLoad())
 
-	go func() {
-		time.Sleep(5 * time.Second)
-		a.window.SetContent(layoutMain())
-	}()
+	setupTray()
+
+	go handleSignals()
+
+	go startup(0)
 
 	a.window.ShowAndRun()
 }
@@ -0,0 +1,359 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/globals"
+)
+
+// One sample of the node and network state
+type Sample struct {
+	Time        int64   `json:"t"`
+	Height      int64   `json:"h"`
+	BlockTime   float64 `json:"bt"`
+	Difficulty  uint64  `json:"d"`
+	NetHashrate float64 `json:"nh"`
+	Peers       uint64  `json:"p"`
+	Mempool     int     `json:"mp"`
+	Hashrate    float64 `json:"lh"`
+}
+
+// Ring of samples covering the longest chart range, optionally mirrored to disk
+type Metrics struct {
+	sync.RWMutex
+	samples []Sample
+	next    int
+	full    bool
+	last    time.Time
+	network string
+	file    *os.File
+	path    string
+	written time.Time // when the file was last rewritten from the ring
+}
+
+// A charted value of a sample
+type Metric struct {
+	label  string
+	value  func(s Sample) float64
+	format func(v float64) string
+}
+
+const (
+	METRICS_INTERVAL = 10 * time.Second
+	METRICS_RANGE    = 7 * 24 * time.Hour
+	METRICS_SIZE     = int(METRICS_RANGE / METRICS_INTERVAL)
+	METRICS_REWRITE  = time.Hour // the file grows by appends in between
+	CHART_POINTS     = 240
+)
+
+var metrics = Metrics{samples: make([]Sample, METRICS_SIZE)}
+
+var chartRanges = map[string]time.Duration{
+	"1h":  time.Hour,
+	"24h": 24 * time.Hour,
+	"7d":  METRICS_RANGE,
+}
+
+var chartMetrics = []Metric{
+	{"HEIGHT", func(s Sample) float64 { return float64(s.Height) }, func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+	{"BLOCK  TIME", func(s Sample) float64 { return s.BlockTime }, func(v float64) string { return fmt.Sprintf("%.2f s", v) }},
+	{"DIFFICULTY", func(s Sample) float64 { return float64(s.Difficulty) }, func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+	{"NETWORK  HASHRATE", func(s Sample) float64 { return s.NetHashrate }, formatHashrate},
+	{"PEERS", func(s Sample) float64 { return float64(s.Peers) }, func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+	{"MEMPOOL", func(s Sample) float64 { return float64(s.Mempool) }, func(v float64) string { return fmt.Sprintf("%.0f", v) }},
+	{"LOCAL  HASHRATE", func(s Sample) float64 { return s.Hashrate }, formatHashrate},
+}
+
+func formatHashrate(h float64) string {
+	switch {
+	case h > 1000000000000:
+		return fmt.Sprintf("%.3f TH/s", h/1000000000000.0)
+	case h > 1000000000:
+		return fmt.Sprintf("%.3f GH/s", h/1000000000.0)
+	case h > 1000000:
+		return fmt.Sprintf("%.3f MH/s", h/1000000.0)
+	case h > 1000:
+		return fmt.Sprintf("%.3f KH/s", h/1000.0)
+	}
+
+	return fmt.Sprintf("%.0f H/s", h)
+}
+
+func metricsPath(network string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "Netrunner", "metrics_"+network+".jsonl"), nil
+}
+
+// Take a sample from status when one is due, called from update()
+func recordMetrics() {
+	now := time.Now()
+	if now.Sub(metrics.last) < METRICS_INTERVAL {
+		return
+	}
+
+	hashrate := 0.0
+	if m.Mission == 1 {
+		hashrate = m.Speed
+	}
+
+	metrics.add(Sample{
+		Time:        now.Unix(),
+		Height:      status.height,
+		BlockTime:   float64(status.block_time),
+		Difficulty:  status.difficulty,
+		NetHashrate: status.net_hashrate,
+		Peers:       status.peers,
+		Mempool:     status.tx_pool,
+		Hashrate:    hashrate,
+	})
+}
+
+func (r *Metrics) add(s Sample) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.samples[r.next] = s
+	r.next = (r.next + 1) % len(r.samples)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.last = time.Unix(s.Time, 0)
+
+	if r.file == nil {
+		return
+	}
+
+	if data, err := json.Marshal(s); err == nil {
+		r.file.Write(append(data, '\n'))
+	}
+
+	// Appends alone would grow the file past the range kept
+	if time.Since(r.written) >= METRICS_REWRITE {
+		if err := r.rewrite(r.path); err != nil {
+			globals.Logger.Error(err, "[Netrunner] Metrics history unavailable", "path", r.path)
+		}
+	}
+}
+
+// Replace the file with the samples of the ring within the range kept and append to it
+// from there on, the caller holds the lock
+func (r *Metrics) rewrite(path string) error {
+	tmp := path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(file)
+	cutoff := time.Now().Add(-METRICS_RANGE).Unix()
+	start := 0
+	if r.full {
+		start = r.next
+	}
+	for i := 0; i < len(r.samples); i++ {
+		if s := r.samples[(start+i)%len(r.samples)]; s.Time != 0 && s.Time >= cutoff {
+			data, _ := json.Marshal(s)
+			w.Write(append(data, '\n'))
+		}
+	}
+	err = w.Flush()
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	// Windows does not rename over an open file
+	if r.file != nil {
+		r.file.Close()
+		r.file = nil
+	}
+
+	if err = os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+
+	if r.file, err = os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600); err != nil {
+		return err
+	}
+	r.path = path
+	r.written = time.Now()
+
+	return nil
+}
+
+// Samples newer than since, oldest first
+func (r *Metrics) since(since time.Time) (list []Sample) {
+	r.RLock()
+	defer r.RUnlock()
+
+	start := 0
+	if r.full {
+		start = r.next
+	}
+
+	cutoff := since.Unix()
+	for i := 0; i < len(r.samples); i++ {
+		s := r.samples[(start+i)%len(r.samples)]
+		if s.Time == 0 || s.Time < cutoff {
+			continue
+		}
+		list = append(list, s)
+	}
+
+	return
+}
+
+// Reset the ring for the current network, loading saved history when kept on disk
+func openMetrics(disk bool) {
+	network := filepath.Base(globals.GetDataDirectory())
+
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	if metrics.file != nil {
+		metrics.file.Close()
+		metrics.file = nil
+	}
+
+	if metrics.network != network {
+		metrics.samples = make([]Sample, METRICS_SIZE)
+		metrics.next = 0
+		metrics.full = false
+		metrics.last = time.Time{}
+		metrics.network = network
+	}
+
+	if !disk {
+		return
+	}
+
+	path, err := metricsPath(network)
+	if err != nil {
+		globals.Logger.Error(err, "[Netrunner] Metrics history unavailable")
+		return
+	}
+
+	kept, err := loadMetrics(path)
+	if err != nil && !os.IsNotExist(err) {
+		globals.Logger.Error(err, "[Netrunner] Could not read metrics history", "path", path)
+	}
+
+	// Samples already in memory win over the file, the file is rewritten with what is kept
+	if metrics.last.IsZero() {
+		for _, s := range kept {
+			metrics.samples[metrics.next] = s
+			metrics.next = (metrics.next + 1) % len(metrics.samples)
+			if metrics.next == 0 {
+				metrics.full = true
+			}
+			metrics.last = time.Unix(s.Time, 0)
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		globals.Logger.Error(err, "[Netrunner] Metrics history unavailable")
+		return
+	}
+
+	if err = metrics.rewrite(path); err != nil {
+		globals.Logger.Error(err, "[Netrunner] Metrics history unavailable")
+		return
+	}
+
+	globals.Logger.V(1).Info("[Netrunner] Metrics history on disk", "path", path, "samples", len(kept))
+}
+
+// Read saved samples within the longest chart range
+func loadMetrics(path string) (list []Sample, err error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	cutoff := time.Now().Add(-METRICS_RANGE).Unix()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var s Sample
+		if json.Unmarshal(scanner.Bytes(), &s) != nil || s.Time < cutoff {
+			continue
+		}
+		list = append(list, s)
+	}
+
+	if len(list) > METRICS_SIZE {
+		list = list[len(list)-METRICS_SIZE:]
+	}
+
+	return list, scanner.Err()
+}
+
+func closeMetrics() {
+	metrics.Lock()
+	defer metrics.Unlock()
+
+	if metrics.file != nil {
+		metrics.file.Close()
+		metrics.file = nil
+	}
+}
+
+// Average samples into at most n points so long ranges stay cheap to draw
+func downsample(list []Sample, value func(s Sample) float64, n int) []float64 {
+	points := make([]float64, len(list))
+	for i, s := range list {
+		points[i] = value(s)
+	}
+
+	return downsampleValues(points, n)
+}
+
+func downsampleValues(values []float64, n int) []float64 {
+	if len(values) <= n {
+		return values
+	}
+
+	points := make([]float64, n)
+	for i := 0; i < n; i++ {
+		from := i * len(values) / n
+		to := (i + 1) * len(values) / n
+
+		sum := 0.0
+		for _, v := range values[from:to] {
+			sum += v
+		}
+		points[i] = sum / float64(to-from)
+	}
+
+	return points
+}
@@ -47,6 +47,8 @@ type Miner struct {
 	Blocks      uint64
 	MiniBlocks  uint64
 	Hashrate    string
+	Speed       float64
+	Started     time.Time
 	NWHashrate  string
 	Connection  *websocket.Conn
 	Label       *canvas.Text
@@ -72,9 +74,11 @@ var Difficulty uint64
 var our_height int64
 var block_counter uint64
 var mini_block_counter uint64
+var miners sync.WaitGroup
 
 func startRunner(w string, d string, t int) {
 	m.Mission = 1
+	m.Started = time.Now()
 
 	globals.Arguments["--wallet-address"] = w
 	globals.Arguments["--daemon-rpc-address"] = d
@@ -115,6 +119,11 @@ func startRunner(w string, d string, t int) {
 
 	globals.Logger.Info(fmt.Sprintf("[Miner] System will mine to \"%s\" with %d threads. Good Luck!!", m.Address, m.Threads))
 
+	// The simulator takes every hash as a miniblock, one thread paced to the block time does
+	if status.simulator {
+		m.Threads = 1
+	}
+
 	if m.Threads < 1 || iterations < 1 || m.Threads > 2048 {
 		iterations = 1
 		m.Threads = 1
@@ -180,6 +189,7 @@ func startRunner(w string, d string, t int) {
 					m.MiniBlocks = mini_block_counter
 					m.NWHashrate = hash_rate_string
 					m.Hashrate = mining_string
+					m.Speed = mining_speed
 
 					last_our_height = our_height
 					last_best_height = best_height
@@ -195,6 +205,7 @@ func startRunner(w string, d string, t int) {
 	}
 
 	if m.Mission == 1 {
+		miners.Add(m.Threads + 1)
 		go getwork(m.Address)
 
 		for i := 0; i < m.Threads; i++ {
@@ -223,8 +234,12 @@ func random_execution(wg *sync.WaitGroup, iterations int) {
 var connection_mutex sync.Mutex
 
 func getwork(wallet_address string) {
+	defer miners.Done()
+
 	if m.Mission == 0 {
-		m.Connection.Close()
+		if m.Connection != nil {
+			m.Connection.Close()
+		}
 		return
 	}
 	var err error
@@ -240,10 +255,14 @@ func getwork(wallet_address string) {
 		dialer.TLSClientConfig = &tls.Config{
 			InsecureSkipVerify: true,
 		}
+		connection_mutex.Lock()
 		m.Connection, _, err = websocket.DefaultDialer.Dial(u.String(), nil)
+		connection_mutex.Unlock()
 		if err != nil {
 			globals.Logger.Info("[Miner] Will try in 10 secs", "server adress", m.Daemon)
-			time.Sleep(10 * time.Second)
+			for i := 0; i < 10 && m.Mission == 1; i++ {
+				time.Sleep(time.Second)
+			}
 
 			continue
 		}
@@ -272,7 +291,7 @@ func getwork(wallet_address string) {
 
 		block_counter = job.Blocks
 		mini_block_counter = job.MiniBlocks
-		hash_rate = job.Difficultyuint64
+		hash_rate = uint64(status.net_hashrate)
 		our_height = int64(job.Height)
 		Difficulty = job.Difficultyuint64
 
@@ -282,6 +301,8 @@ func getwork(wallet_address string) {
 }
 
 func mineblock(tid int) {
+	defer miners.Done()
+
 	var diff big.Int
 	var work [block.MINIBLOCK_SIZE]byte
 	var random_buf [12]byte
@@ -340,6 +361,11 @@ func mineblock(tid int) {
 					defer connection_mutex.Unlock()
 					m.Connection.WriteJSON(rpc.SubmitBlock_Params{JobID: myjob.JobID, MiniBlockhashing_blob: fmt.Sprintf("%x", work[:])})
 				}()
+
+				if status.simulator {
+					simulatorPace()
+					break
+				}
 			}
 		}
 	}
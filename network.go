@@ -0,0 +1,189 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"os"
+	"sync"
+
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/deroproject/derohe/globals"
+)
+
+// Settings that only make sense on one network, addresses and ports differ between them
+type NetworkSettings struct {
+	Reward  string `json:"reward,omitempty"`
+	RPCMode string `json:"rpc_mode,omitempty"`
+	RPCBind string `json:"rpc_bind,omitempty"`
+}
+
+// Held by the daemon UI loop so a restart waits for the previous loop to finish
+var daemonUI sync.WaitGroup
+
+// Set when the miner should come back once the daemon is synced again
+var resumeMiner bool
+
+// Handed to a relaunched Netrunner to bring the daemon, or daemon and miner, back up
+const (
+	RESUME_ENV    = "NETRUNNER_RESUME"
+	RESUME_DAEMON = "daemon"
+	RESUME_MINER  = "miner"
+)
+
+const (
+	NETWORK_MAINNET   = "mainnet"
+	NETWORK_TESTNET   = "testnet"
+	NETWORK_SIMULATOR = "simulator"
+)
+
+var networkLabels = map[string]string{
+	NETWORK_MAINNET:   "Mainnet",
+	NETWORK_TESTNET:   "Testnet",
+	NETWORK_SIMULATOR: "Simulator",
+}
+
+// Name of the selected network, used as its settings key
+func networkName() string {
+	switch {
+	case status.simulator:
+		return NETWORK_SIMULATOR
+	case status.network:
+		return NETWORK_TESTNET
+	default:
+		return NETWORK_MAINNET
+	}
+}
+
+func rpcPort() int {
+	if status.simulator {
+		return SIMULATOR_RPC_PORT
+	} else if status.network {
+		return DEFAULT_DAEMON_TESTNET_RPC_PORT
+	}
+
+	return DEFAULT_DAEMON_MAINNET_RPC_PORT
+}
+
+func workPort() int {
+	if status.simulator {
+		return SIMULATOR_WORK_PORT
+	} else if status.network {
+		return DEFAULT_DAEMON_TESTNET_WORK_PORT
+	}
+
+	return DEFAULT_DAEMON_MAINNET_WORK_PORT
+}
+
+// Keep the settings of the current network aside
+func storeNetworkSettings() {
+	if status.networks == nil {
+		status.networks = map[string]NetworkSettings{}
+	}
+
+	status.networks[networkName()] = NetworkSettings{
+		Reward:  status.integrator,
+		RPCMode: status.rpc_mode,
+		RPCBind: status.rpc_bind,
+	}
+}
+
+// Bring back the settings of the current network, a network never used starts empty
+func loadNetworkSettings() {
+	n := status.networks[networkName()]
+	status.integrator = n.Reward
+	status.rpc_mode = n.RPCMode
+	status.rpc_bind = n.RPCBind
+}
+
+// Point derod at the selected network, the simulator runs on a testnet config
+func initGlobals() {
+	globals.Arguments["--testnet"] = status.network
+	globals.Arguments["--simulator"] = status.simulator
+	setSimulatorGenesis(status.simulator)
+	globals.Initialize()
+}
+
+// The daemon and miner state a relaunch asked for, read once
+func takeResume() string {
+	resume := os.Getenv(RESUME_ENV)
+	os.Unsetenv(RESUME_ENV)
+
+	return resume
+}
+
+// Select a network by name, the daemon must not be running
+func setNetwork(name string) {
+	storeNetworkSettings()
+
+	// Addresses differ between networks, the wallets cannot follow
+	closeWallets()
+
+	status.network = name != NETWORK_MAINNET
+	status.simulator = name == NETWORK_SIMULATOR
+	initGlobals()
+
+	loadNetworkSettings()
+
+	globals.Logger.Info("[Netrunner] Network selected", "network", name, "data_dir", globals.GetDataDirectory())
+}
+
+// Move to another network, a running daemon and miner are stopped first and
+// brought back up on the new network, apply refreshes the config widgets.
+// Once derod ran in this process Netrunner relaunches itself on the new network
+func switchNetwork(name string, apply func()) {
+	running := bw.chain != nil
+	mining := m.Mission == 1
+
+	progress := dialog.NewCustomWithoutButtons("Switching to "+networkLabels[name], widget.NewProgressBarInfinite(), a.window)
+	if running {
+		progress.Show()
+		defer progress.Hide()
+
+		globals.Logger.Info("[Netrunner] Stopping daemon to switch network", "from", networkName(), "to", name)
+		stopDaemon()
+		daemonUI.Wait()
+	}
+
+	setNetwork(name)
+	apply()
+	saveSettings()
+
+	if bw.ran {
+		resume := ""
+		if running {
+			resume = RESUME_DAEMON
+			if mining {
+				resume = RESUME_MINER
+			}
+		}
+
+		globals.Logger.Info("[Netrunner] Relaunching to switch network", "network", name)
+		restartApp(resume)
+		return
+	}
+
+	if !running || runDaemon == nil {
+		return
+	}
+
+	resumeMiner = mining
+	runDaemon()
+
+	if bw.chain == nil {
+		resumeMiner = false
+		globals.Logger.Error(nil, "[Netrunner] Daemon did not restart after network switch", "network", name)
+	}
+}
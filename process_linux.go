@@ -0,0 +1,106 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// whether a process with pid is running, signal 0 only probes for existence
+func processAlive(pid int) bool {
+	err := syscall.Kill(pid, 0)
+	return err == nil || err == syscall.EPERM
+}
+
+// executable name of pid, comm is only read when exe is not ours to see and is cut to COMM_LENGTH
+func processName(pid int) string {
+	if exe, err := os.Readlink(fmt.Sprintf("/proc/%d/exe", pid)); err == nil {
+		return filepath.Base(strings.TrimSuffix(exe, " (deleted)"))
+	}
+
+	comm, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return ""
+	}
+
+	return strings.TrimSpace(string(comm))
+}
+
+// find the pid listening on port by matching the socket inode from /proc/net against /proc/*/fd
+func portOwner(network string, port int) (int, error) {
+	inodes := map[string]bool{}
+
+	for _, table := range []string{"/proc/net/" + network, "/proc/net/" + network + "6"} {
+		file, err := os.Open(table)
+		if err != nil {
+			continue
+		}
+
+		scanner := bufio.NewScanner(file)
+		scanner.Scan() // header
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 10 {
+				continue
+			}
+
+			local := strings.Split(fields[1], ":")
+			if len(local) != 2 {
+				continue
+			}
+
+			p, err := strconv.ParseInt(local[1], 16, 32)
+			if err != nil || int(p) != port {
+				continue
+			}
+
+			// TCP sockets must be listening (0A), UDP sockets are unconnected (07)
+			if (network == "tcp" && fields[3] == "0A") || (network == "udp" && fields[3] == "07") {
+				inodes["socket:["+fields[9]+"]"] = true
+			}
+		}
+		file.Close()
+	}
+
+	if len(inodes) == 0 {
+		return 0, fmt.Errorf("no listener found on port %d", port)
+	}
+
+	procs, err := filepath.Glob("/proc/[0-9]*/fd/*")
+	if err != nil {
+		return 0, err
+	}
+
+	for _, fd := range procs {
+		link, err := os.Readlink(fd)
+		if err != nil || !inodes[link] {
+			continue
+		}
+
+		pid, err := strconv.Atoi(strings.Split(fd, "/")[2])
+		if err == nil {
+			return pid, nil
+		}
+	}
+
+	return 0, fmt.Errorf("owner of port %d is not visible", port)
+}
@@ -0,0 +1,116 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"encoding/binary"
+	"fmt"
+	"path/filepath"
+	"syscall"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	STILL_ACTIVE                 = 259
+	TCP_TABLE_OWNER_PID_LISTENER = 3
+	UDP_TABLE_OWNER_PID          = 1
+)
+
+var libiphlpapi uintptr
+var getExtendedTcpTable uintptr
+var getExtendedUdpTable uintptr
+
+func init() {
+	libiphlpapi = doLoadLibrary("iphlpapi.dll")
+	getExtendedTcpTable = doGetProcAddress(libiphlpapi, "GetExtendedTcpTable")
+	getExtendedUdpTable = doGetProcAddress(libiphlpapi, "GetExtendedUdpTable")
+}
+
+func processAlive(pid int) bool {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return err == windows.ERROR_ACCESS_DENIED
+	}
+	defer windows.CloseHandle(h)
+
+	var code uint32
+	if err = windows.GetExitCodeProcess(h, &code); err != nil {
+		return false
+	}
+
+	return code == STILL_ACTIVE
+}
+
+func processName(pid int) string {
+	h, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		return ""
+	}
+	defer windows.CloseHandle(h)
+
+	buf := make([]uint16, windows.MAX_PATH)
+	size := uint32(len(buf))
+	if err = windows.QueryFullProcessImageName(h, 0, &buf[0], &size); err != nil {
+		return ""
+	}
+
+	return filepath.Base(windows.UTF16ToString(buf[:size]))
+}
+
+// find the pid bound to port using the IPv4 owner tables from iphlpapi
+func portOwner(network string, port int) (int, error) {
+	proc := getExtendedTcpTable
+	class := uintptr(TCP_TABLE_OWNER_PID_LISTENER)
+	row := 24 // MIB_TCPROW_OWNER_PID
+	offset := 8
+	if network == "udp" {
+		proc = getExtendedUdpTable
+		class = UDP_TABLE_OWNER_PID
+		row = 12 // MIB_UDPROW_OWNER_PID
+		offset = 4
+	}
+
+	if proc == 0 {
+		return 0, fmt.Errorf("port owner lookup is not available")
+	}
+
+	var size uint32
+	syscall.Syscall6(proc, 6, 0, uintptr(unsafe.Pointer(&size)), 0, syscall.AF_INET, class, 0)
+	if size == 0 {
+		return 0, fmt.Errorf("port owner lookup failed")
+	}
+
+	buf := make([]byte, size)
+	ret, _, _ := syscall.Syscall6(proc, 6, uintptr(unsafe.Pointer(&buf[0])), uintptr(unsafe.Pointer(&size)), 0, syscall.AF_INET, class, 0)
+	if ret != 0 {
+		return 0, fmt.Errorf("port owner lookup failed with code %d", ret)
+	}
+
+	count := int(binary.LittleEndian.Uint32(buf))
+	for i := 0; i < count; i++ {
+		r := buf[4+i*row : 4+(i+1)*row]
+
+		// ports are stored in network byte order in the low 16 bits
+		p := int(binary.BigEndian.Uint16(r[offset : offset+2]))
+		if p == port {
+			return int(binary.LittleEndian.Uint32(r[row-4:])), nil
+		}
+	}
+
+	return 0, fmt.Errorf("no listener found on port %d", port)
+}
@@ -0,0 +1,86 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"time"
+)
+
+// Expected output of a hashrate over a period, DERO is in atomic units
+type Earnings struct {
+	period     time.Duration
+	miniblocks float64
+	dero       float64
+	cost       float64 // power cost in the currency the kWh price was given in
+}
+
+var earningPeriods = []time.Duration{time.Hour, 24 * time.Hour, 7 * 24 * time.Hour}
+
+// The network finds MINIBLOCKS_PER_BLOCK miniblocks every block time and shares the block
+// reward among them, a hashrate gets the part of both matching its share of the network
+func expectedEarnings(local float64, network float64, reward uint64, watts float64, price float64, period time.Duration) (e Earnings) {
+	e.period = period
+	e.cost = powerCost(watts, price, period)
+
+	if local <= 0 || network <= 0 {
+		return
+	}
+
+	blocks := period.Seconds() / float64(TARGET_BLOCK_TIME)
+	fraction := local / network
+
+	e.miniblocks = blocks * float64(MINIBLOCKS_PER_BLOCK) * fraction
+	e.dero = blocks * float64(reward) * fraction
+
+	return
+}
+
+// Cost of drawing watts for a period at a price per kWh
+func powerCost(watts float64, price float64, period time.Duration) float64 {
+	if watts <= 0 || price <= 0 {
+		return 0
+	}
+
+	return watts / 1000 * period.Hours() * price
+}
+
+// Miniblocks the recorded hashrate should have found since start, each sample
+// covers the time to the next one and gaps in the history are not counted
+func expectedMiniblocks(samples []Sample, start time.Time) (expected float64) {
+	for i := 1; i < len(samples); i++ {
+		prev, cur := samples[i-1], samples[i]
+		if prev.Time < start.Unix() || prev.Hashrate <= 0 || prev.NetHashrate <= 0 {
+			continue
+		}
+
+		seconds := float64(cur.Time - prev.Time)
+		if seconds <= 0 || seconds > 2*METRICS_INTERVAL.Seconds() {
+			continue
+		}
+
+		expected += seconds / float64(TARGET_BLOCK_TIME) * float64(MINIBLOCKS_PER_BLOCK) * prev.Hashrate / prev.NetHashrate
+	}
+
+	return
+}
+
+// Found against expected in percent, above 100 is better than average luck
+func luck(found uint64, expected float64) float64 {
+	if expected <= 0 {
+		return 0
+	}
+
+	return float64(found) / expected * 100
+}
@@ -0,0 +1,294 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/globals"
+)
+
+// The derod RPC server has no access controls of its own, so when an allowlist or
+// credentials are set the daemon is bound to loopback and the guard fronts it
+type RPCGuard struct {
+	server *http.Server
+	proxy  *httputil.ReverseProxy
+	allow  []*net.IPNet
+	user   string
+	pass   string
+}
+
+const (
+	RPC_BIND_LOOPBACK = "Loopback"
+	RPC_BIND_LAN      = "LAN"
+	RPC_BIND_CUSTOM   = "Custom"
+)
+
+// Parse a comma or space separated list of CIDRs, bare IPs are treated as single hosts
+func parseAllowlist(s string) (list []*net.IPNet, err error) {
+	fields := strings.FieldsFunc(s, func(r rune) bool {
+		return r == ',' || r == ' ' || r == ';'
+	})
+
+	for _, f := range fields {
+		if !strings.Contains(f, "/") {
+			ip := net.ParseIP(f)
+			if ip == nil {
+				return nil, fmt.Errorf("%s is not a valid IP or CIDR", f)
+			}
+
+			if ip.To4() != nil {
+				f += "/32"
+			} else {
+				f += "/128"
+			}
+		}
+
+		_, cidr, err := net.ParseCIDR(f)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid IP or CIDR", f)
+		}
+
+		list = append(list, cidr)
+	}
+
+	return
+}
+
+// Find a private address of this machine without sending any traffic
+func lanIP() net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		return nil
+	}
+
+	for _, a := range addrs {
+		if ipnet, ok := a.(*net.IPNet); ok {
+			if ip := ipnet.IP.To4(); ip != nil && ip.IsPrivate() {
+				return ip
+			}
+		}
+	}
+
+	return nil
+}
+
+// Resolve the RPC bind address for the selected mode
+func rpcBindAddress(mode string, custom string, port int) (string, error) {
+	switch mode {
+	case RPC_BIND_LAN:
+		ip := lanIP()
+		if ip == nil {
+			return "", fmt.Errorf("no LAN interface found")
+		}
+		return net.JoinHostPort(ip.String(), strconv.Itoa(port)), nil
+	case RPC_BIND_CUSTOM:
+		host, p, err := net.SplitHostPort(custom)
+		if err != nil {
+			return "", fmt.Errorf("RPC address must be in ip:port form")
+		}
+
+		if n, err := strconv.Atoi(p); err != nil || n < 1 || n > 65535 {
+			return "", fmt.Errorf("RPC port is invalid")
+		}
+
+		if host != "" && net.ParseIP(host) == nil {
+			return "", fmt.Errorf("RPC host must be an IP address")
+		}
+
+		return custom, nil
+	default:
+		return net.JoinHostPort(DEFAULT_DAEMON_LOCAL_ADDRESS, strconv.Itoa(port)), nil
+	}
+}
+
+// Anything other than loopback or a private range is reachable from the internet
+func isPublicBind(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsUnspecified() {
+		return true
+	}
+
+	return !ip.IsLoopback() && !ip.IsPrivate() && !ip.IsLinkLocalUnicast()
+}
+
+// derod and the wallet RPC servers read globals.Arguments while they run and a write to the
+// map meanwhile is a fatal error. The RPC keys are only changed under argsLock, by putting a
+// changed copy in place, so a reader keeps using the map it already had
+var argsLock sync.Mutex
+
+// Caller holds argsLock
+func replaceArguments(changes map[string]interface{}) {
+	args := make(map[string]interface{}, len(globals.Arguments)+len(changes))
+	for k, v := range globals.Arguments {
+		args[k] = v
+	}
+	for k, v := range changes {
+		args[k] = v
+	}
+
+	globals.Arguments = args
+}
+
+func setArgument(key string, value interface{}) {
+	argsLock.Lock()
+	defer argsLock.Unlock()
+
+	replaceArguments(map[string]interface{}{key: value})
+}
+
+func argument(key string) interface{} {
+	argsLock.Lock()
+	defer argsLock.Unlock()
+
+	return globals.Arguments[key]
+}
+
+// Grab an unused loopback port for the daemon to listen on behind the guard
+func freeLocalAddress() (string, error) {
+	l, err := net.Listen("tcp", DEFAULT_DAEMON_LOCAL_ADDRESS+":0")
+	if err != nil {
+		return "", err
+	}
+	defer l.Close()
+
+	return l.Addr().String(), nil
+}
+
+// Configure the daemon RPC bind, starting the guard in front of it if access controls are set
+func setRPCBind(bind string, allowlist string, user string, pass string) error {
+	allow, err := parseAllowlist(allowlist)
+	if err != nil {
+		return err
+	}
+
+	if len(allow) == 0 && user == "" && pass == "" {
+		setArgument("--rpc-bind", bind)
+		return nil
+	}
+
+	target, err := freeLocalAddress()
+	if err != nil {
+		return err
+	}
+
+	bw.guard, err = startGuard(bind, target, allow, user, pass)
+	if err != nil {
+		return err
+	}
+
+	setArgument("--rpc-bind", target)
+
+	return nil
+}
+
+func startGuard(listen string, target string, allow []*net.IPNet, user string, pass string) (*RPCGuard, error) {
+	l, err := net.Listen("tcp", listen)
+	if err != nil {
+		return nil, fmt.Errorf("could not bind RPC to %s: %w", listen, err)
+	}
+
+	g := &RPCGuard{
+		proxy: httputil.NewSingleHostReverseProxy(&url.URL{Scheme: "http", Host: target}),
+		allow: allow,
+		user:  user,
+		pass:  pass,
+	}
+	g.server = &http.Server{Handler: g, ReadHeaderTimeout: 10 * time.Second}
+
+	go func() {
+		if err := g.server.Serve(l); err != http.ErrServerClosed {
+			globals.Logger.Error(err, "[Netrunner] RPC guard stopped")
+		}
+	}()
+
+	globals.Logger.Info("[Netrunner] RPC guard listening", "address", listen, "allowlist", len(allow), "auth", user != "" || pass != "")
+
+	return g, nil
+}
+
+func (g *RPCGuard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !g.allowed(r.RemoteAddr) {
+		globals.Logger.V(1).Info("[Netrunner] RPC request refused by allowlist", "remote", r.RemoteAddr)
+		http.Error(w, "Forbidden", http.StatusForbidden)
+		return
+	}
+
+	if g.user != "" || g.pass != "" {
+		user, pass, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(user), []byte(g.user)) != 1 || subtle.ConstantTimeCompare([]byte(pass), []byte(g.pass)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="Netrunner"`)
+			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			return
+		}
+
+		// Credentials stop at the guard
+		r.Header.Del("Authorization")
+	}
+
+	g.proxy.ServeHTTP(w, r)
+}
+
+// Loopback clients are always allowed so the local wallet keeps working
+func (g *RPCGuard) allowed(remote string) bool {
+	if len(g.allow) == 0 {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(remote)
+	if err != nil {
+		return false
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	if ip.IsLoopback() {
+		return true
+	}
+
+	for _, cidr := range g.allow {
+		if cidr.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (g *RPCGuard) Stop() {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	g.server.Shutdown(ctx)
+}
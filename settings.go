@@ -0,0 +1,190 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"fyne.io/fyne/v2"
+	"github.com/deroproject/derohe/globals"
+)
+
+// Settings persisted between sessions, stored as JSON in the user config directory,
+// reward and RPC bind at the top level belong to the selected network
+type Settings struct {
+	Network   string  `json:"network,omitempty"`
+	DataDir   string  `json:"data_dir,omitempty"`
+	RPCMode   string  `json:"rpc_mode,omitempty"`
+	RPCBind   string  `json:"rpc_bind,omitempty"`
+	RPCAllow  string  `json:"rpc_allow,omitempty"`
+	RPCUser   string  `json:"rpc_user,omitempty"`
+	RPCPass   string  `json:"rpc_pass,omitempty"`
+	ClogLevel string  `json:"clog_level,omitempty"`
+	FlogLevel string  `json:"flog_level,omitempty"`
+	Reward    string  `json:"reward,omitempty"`
+	Threads   int     `json:"threads,omitempty"`
+	AutoStart bool    `json:"auto_start,omitempty"`
+	AutoMine  bool    `json:"auto_mine,omitempty"`
+	AtLogin   bool    `json:"at_login,omitempty"`
+	OnClose   string  `json:"on_close,omitempty"`
+	Window    *Size   `json:"window,omitempty"`
+	Explorer  *Size   `json:"explorer,omitempty"`
+	History   bool    `json:"history,omitempty"`
+	Watts     float64 `json:"watts,omitempty"`
+	KWhPrice  float64 `json:"kwh_price,omitempty"`
+	Gateway   bool    `json:"gateway,omitempty"`
+
+	Networks map[string]NetworkSettings `json:"networks,omitempty"`
+}
+
+// Window size in device independent units, fyne has no API for window position
+type Size struct {
+	Width  float32 `json:"width"`
+	Height float32 `json:"height"`
+}
+
+const SETTINGS_FILE = "config.json"
+
+func settingsPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "Netrunner", SETTINGS_FILE), nil
+}
+
+// Load saved settings into status, a missing file leaves the defaults in place
+func loadSettings() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	var s Settings
+	if err = json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	status.network = s.Network == NETWORK_TESTNET || s.Network == NETWORK_SIMULATOR
+	status.simulator = s.Network == NETWORK_SIMULATOR
+	status.networks = s.Networks
+	status.data_dir = s.DataDir
+	status.rpc_mode = s.RPCMode
+	status.rpc_bind = s.RPCBind
+	status.rpc_allow = s.RPCAllow
+	status.rpc_user = s.RPCUser
+	status.rpc_pass = s.RPCPass
+	status.clog_level = s.ClogLevel
+	status.flog_level = s.FlogLevel
+	status.integrator = s.Reward
+	status.threads = s.Threads
+	status.auto_start = s.AutoStart
+	status.auto_mine = s.AutoMine
+	status.at_login = s.AtLogin
+	status.close_action = s.OnClose
+	status.metrics_disk = s.History
+	status.watts = s.Watts
+	status.kwh_price = s.KWhPrice
+	status.gateway = s.Gateway
+	restoreSize(a.window, s.Window)
+	restoreSize(a.explorer, s.Explorer)
+
+	return nil
+}
+
+func windowSize(w fyne.Window) *Size {
+	if w == nil {
+		return nil
+	}
+
+	size := w.Canvas().Size()
+	if size.Width < 1 || size.Height < 1 {
+		return nil
+	}
+
+	return &Size{Width: fyne.Max(size.Width, WINDOW_MIN_WIDTH), Height: fyne.Max(size.Height, WINDOW_MIN_HEIGHT)}
+}
+
+// Apply a saved size, anything smaller than the minimum is ignored
+func restoreSize(w fyne.Window, size *Size) {
+	if w == nil || size == nil || size.Width < WINDOW_MIN_WIDTH || size.Height < WINDOW_MIN_HEIGHT {
+		return
+	}
+
+	w.Resize(fyne.NewSize(size.Width, size.Height))
+	w.CenterOnScreen()
+}
+
+// Save the current settings, the file holds RPC credentials so it is kept private
+func saveSettings() error {
+	path, err := settingsPath()
+	if err != nil {
+		return err
+	}
+
+	storeNetworkSettings()
+
+	s := Settings{
+		Network:   networkName(),
+		DataDir:   status.data_dir,
+		RPCMode:   status.rpc_mode,
+		RPCBind:   status.rpc_bind,
+		RPCAllow:  status.rpc_allow,
+		RPCUser:   status.rpc_user,
+		RPCPass:   status.rpc_pass,
+		ClogLevel: status.clog_level,
+		FlogLevel: status.flog_level,
+		Reward:    status.integrator,
+		Threads:   m.Threads,
+		AutoStart: status.auto_start,
+		AutoMine:  status.auto_mine,
+		AtLogin:   status.at_login,
+		OnClose:   status.close_action,
+		Window:    windowSize(a.window),
+		Explorer:  windowSize(a.explorer),
+		History:   status.metrics_disk,
+		Watts:     status.watts,
+		KWhPrice:  status.kwh_price,
+		Gateway:   status.gateway,
+		Networks:  status.networks,
+	}
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(path, data, 0600); err != nil {
+		globals.Logger.Error(err, "[Netrunner] Could not save settings", "path", path)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,158 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/canvas"
+	"fyne.io/fyne/v2/container"
+	"fyne.io/fyne/v2/dialog"
+	"fyne.io/fyne/v2/widget"
+	"github.com/deroproject/derohe/globals"
+)
+
+// A step of the shutdown sequence, run in order
+type shutdownStep struct {
+	name string
+	run  func()
+}
+
+const SHUTDOWN_TIMEOUT = 30 * time.Second
+
+var shutdownOnce sync.Once
+
+// Set when Netrunner starts again after the shutdown, resume is passed on in RESUME_ENV
+var relaunch struct {
+	enabled bool
+	resume  string
+}
+
+// Always call this for a graceful close, safe to call more than once
+func appClose() {
+	shutdownOnce.Do(func() {
+		go shutdown()
+	})
+}
+
+// Shut down and start a new Netrunner with the same arguments
+func restartApp(resume string) {
+	relaunch.enabled = true
+	relaunch.resume = resume
+	appClose()
+}
+
+// The network comes from the saved settings, a --testnet given at launch would override it
+func startRelaunch() error {
+	exe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+
+	var args []string
+	for _, arg := range os.Args[1:] {
+		if arg != "--testnet" {
+			args = append(args, arg)
+		}
+	}
+
+	cmd := exec.Command(exe, args...)
+	cmd.Env = append(os.Environ(), RESUME_ENV+"="+relaunch.resume)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	return cmd.Start()
+}
+
+// Route SIGINT and SIGTERM through the same shutdown as closing the window
+func handleSignals() {
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, os.Interrupt, syscall.SIGTERM)
+
+	sig := <-c
+	globals.Logger.Info("[Netrunner] Received signal, shutting down", "signal", sig.String())
+	appClose()
+
+	// A second signal skips the wait
+	sig = <-c
+	globals.Logger.Info("[Netrunner] Received second signal, forcing exit", "signal", sig.String())
+	os.Exit(1)
+}
+
+func shutdown() {
+	steps := []shutdownStep{
+		{"Stopping miner", stopMiner},
+		{"Stopping dApp gateway", stopGateway},
+		{"Closing wallets", closeWallets},
+		{"Stopping RPC server", stopRPC},
+		{"Closing chain database", stopChain},
+		{"Saving settings", func() {
+			if started {
+				saveSettings()
+			}
+		}},
+	}
+
+	// derod has no way to stop the getwork server, its listener is released with the process
+	timer := time.AfterFunc(SHUTDOWN_TIMEOUT, func() {
+		globals.Logger.Error(nil, "[Netrunner] Shutdown timed out, forcing exit", "timeout", SHUTDOWN_TIMEOUT)
+		os.Exit(1)
+	})
+	defer timer.Stop()
+
+	step := canvas.NewText("", colors.gray)
+	step.TextSize = 12
+	progress := widget.NewProgressBar()
+	progress.Max = float64(len(steps))
+
+	rect := canvas.NewRectangle(colors.darkmatter)
+	rect.SetMinSize(fyne.NewSize(300, 10))
+
+	// Bring the window back from the tray so the progress is visible
+	a.window.Show()
+
+	d := dialog.NewCustomWithoutButtons("Shutting Down", container.NewVBox(rect, step, progress), a.window)
+	d.Show()
+
+	globals.Logger.Info("[Netrunner] Shutting down")
+
+	for i, s := range steps {
+		step.Text = s.name + "..."
+		step.Refresh()
+		progress.SetValue(float64(i))
+
+		start := time.Now()
+		s.run()
+		globals.Logger.V(1).Info("[Netrunner] Shutdown step done", "step", s.name, "took", time.Since(start).Round(time.Millisecond))
+	}
+
+	progress.SetValue(progress.Max)
+	globals.Logger.Info("[Netrunner] Shutdown complete")
+
+	if relaunch.enabled {
+		if err := startRelaunch(); err != nil {
+			globals.Logger.Error(err, "[Netrunner] Relaunch failed")
+		}
+	}
+
+	os.Exit(0)
+}
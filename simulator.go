@@ -0,0 +1,377 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/cmd/derod/rpc"
+	"github.com/deroproject/derohe/config"
+	"github.com/deroproject/derohe/cryptography/crypto"
+	"github.com/deroproject/derohe/globals"
+	"github.com/deroproject/derohe/transaction"
+	"github.com/deroproject/derohe/walletapi"
+	walletrpc "github.com/deroproject/derohe/walletapi/rpcserver"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// A private chain for development, the chain runs in derod simulator mode where
+// difficulty is fixed and proof of work is not checked. Blocks come from the
+// integrated miner through the simulator getwork server, at the fixed difficulty
+// every hash is a miniblock so the miner paces itself to the block time.
+// walletapi sync loops cannot be stopped, so the test wallets stay open between
+// runs and are only closed when the simulator is reset
+type Simulator struct {
+	active  bool
+	genesis *walletapi.Wallet_Disk
+	wallets []*walletapi.Wallet_Disk
+	servers []*walletrpc.RPCServer
+}
+
+const (
+	SIMULATOR_RPC_PORT       = 20000
+	SIMULATOR_WORK_PORT      = 20100
+	SIMULATOR_WALLET_PORT    = 30000
+	SIMULATOR_MINIBLOCK_TIME = time.Duration(config.BLOCK_TIME) * time.Second / (config.MINIBLOCK_HIGHDIFF + 1)
+	SIMULATOR_MINE_DELAY     = 100 * time.Millisecond
+	SIMULATOR_WAIT           = 5 * time.Second
+)
+
+// The seeds of the derohe simulator, so tooling written against it works unchanged
+const simulatorGenesisSeed = "0206a2fca2d2da068dfa8f792ef190a352d656910895f6c541d54877fca95a77"
+
+var simulatorSeeds = []string{
+	"171eeaa899e360bf1a8ada7627aaea9fdad7992463581d935a8838f16b1ff51a",
+	"193faf64d79e9feca5fce8b992b4bb59b86c50f491e2dc475522764ca6666b6b",
+	"2e49383ac5c938c268921666bccfcb5f0c4d43cd3ed125c6c9e72fc5620bc79b",
+	"1c8ee58431e21d1ef022ccf1f53fec36f5e5851d662a3dd96ced3fc155445120",
+	"19182604625563f3ff913bb8fb53b0ade2e0271ca71926edb98c8e39f057d557",
+	"2a3beb8a57baa096512e85902bb5f1833f1f37e79f75227bbf57c4687bfbb002",
+	"055e43ebff20efff612ba6f8128caf990f2bf89aeea91584e63179b9d43cd3ab",
+	"2ccb7fc12e867796dd96e246aceff3fea1fdf78a28253c583017350034c31c81",
+	"279533d87cc4c637bf853e630480da4ee9d4390a282270d340eac52a391fd83d",
+	"03bae8b71519fe8ac3137a3c77d2b6a164672c8691f67bd97548cb6c6f868c67",
+	"2b9022d0c5ee922439b0d67864faeced65ebce5f35d26e0ee0746554d395eb88",
+	"1a63d5cf9955e8f3d6cecde4c9ecbd538089e608741019397824dc6a2e0bfcc1",
+	"10900d25e7dc0cec35fcca9161831a02cb7ed513800368529ba8944eeca6e949",
+	"2af6630905d73ee40864bd48339f297908a0731a6c4c6fa0a27ea574ac4e4733",
+	"2ac9a8984c988fcb54b261d15bc90b5961d673bffa5ff41c8250c7e262cbd606",
+	"040572cec23e6df4f686192b776c197a50591836a3dd02ba2e4a7b7474382ccd",
+	"2b2b029cfbc5d08b5d661e6fa444102d387780bec088f4dd41a4a537bf9762af",
+	"1812298da90ded6457b2a20fd52d09f639584fb470c715617db13959927be7f8",
+	"1eee334e1f533aa1ac018124cf3d5efa20e52f54b05e475f6f2cff3476b4a92f",
+	"2c34e7978ce249aebed33e14cdd5177921ecd78fbe58d33bbec21f22b80af7a5",
+	"083e7fe96e8415ea119ec6c4d0ebe233e86b53bd4e2f7598505317efc23ae34b",
+	"0fd7f8db0ed6cbe3bf300258619d8d4a2ff8132ef3c896f6e3fa65a6c92bdf9a",
+}
+
+var sim Simulator
+
+// The real testnet genesis, the simulator swaps in one paying its genesis wallet
+var testnetGenesisTx = config.Testnet.Genesis_Tx
+var testnetGenesisHash = config.Testnet.Genesis_Block_Hash
+
+var walletsOnce sync.Once
+
+func simulatorSeed(seed string) (*crypto.BNRed, error) {
+	raw, err := hex.DecodeString(seed)
+	if err != nil || len(seed) > 64 {
+		return nil, fmt.Errorf("invalid simulator seed")
+	}
+
+	return new(crypto.BNRed).SetBytes(raw), nil
+}
+
+// Point the testnet genesis at the simulator genesis wallet or back at the real
+// one, globals.Initialize must run afterwards to copy it into the config
+func setSimulatorGenesis(enabled bool) {
+	config.Testnet.Genesis_Tx = testnetGenesisTx
+	config.Testnet.Genesis_Block_Hash = testnetGenesisHash
+
+	if !enabled {
+		return
+	}
+
+	seed, err := simulatorSeed(simulatorGenesisSeed)
+	if err != nil {
+		globals.Logger.Error(err, "[Netrunner] Simulator genesis not set")
+		return
+	}
+
+	account, err := walletapi.Generate_Account_From_Seed(seed)
+	if err != nil {
+		globals.Logger.Error(err, "[Netrunner] Simulator genesis not set")
+		return
+	}
+
+	tx := transaction.Transaction{Transaction_Prefix: transaction.Transaction_Prefix{Version: 1, Value: 112345}}
+	copy(tx.MinerAddress[:], account.GetAddress().PublicKey.EncodeCompressed())
+
+	config.Testnet.Genesis_Tx = fmt.Sprintf("%x", tx.Serialize())
+	globals.Config.Genesis_Tx = config.Testnet.Genesis_Tx
+	genesis := blockchain.Generate_Genesis_Block()
+	config.Testnet.Genesis_Block_Hash = genesis.GetHash()
+}
+
+// Wallets share a single daemon connection inside walletapi, point it at the embedded daemon
+func connectWallets(daemon string) {
+	walletapi.Daemon_Endpoint_Active = daemon
+
+	walletsOnce.Do(func() {
+		walletapi.Initialize_LookupTable(1, 1<<21)
+		go walletapi.Keep_Connectivity()
+	})
+}
+
+// derod and wallet RPC servers read their bind address once running, wait until they listen
+func waitListening(address string) bool {
+	for start := time.Now(); time.Since(start) < SIMULATOR_WAIT; time.Sleep(50 * time.Millisecond) {
+		if conn, err := net.DialTimeout("tcp", address, time.Second); err == nil {
+			conn.Close()
+			return true
+		}
+	}
+
+	return false
+}
+
+// The simulator getwork only listens on loopback, the integrated miner is its client
+func simulatorGetwork() string {
+	return net.JoinHostPort(DEFAULT_DAEMON_LOCAL_ADDRESS, strconv.Itoa(SIMULATOR_WORK_PORT))
+}
+
+// The genesis wallet holds the premine and collects the rewards unless a mining address is set
+func simulatorMiner() string {
+	if addr, err := globals.ParseValidateAddress(status.integrator); err == nil {
+		return addr.String()
+	}
+
+	if sim.genesis != nil {
+		return sim.genesis.GetAddress().String()
+	}
+
+	return ""
+}
+
+// Wait between miniblocks so blocks follow the block time, a block with transactions is mined straight away
+func simulatorPace() {
+	delay := SIMULATOR_MINIBLOCK_TIME
+	if chain := bw.chain; chain != nil {
+		if bl, _, _, _, err := chain.Create_new_block_template_mining(chain.IntegratorAddress()); err == nil && len(bl.Tx_hashes) > 0 {
+			delay = SIMULATOR_MINE_DELAY
+		}
+	}
+
+	start := time.Now()
+	for time.Since(start) < delay && m.Mission == 1 {
+		time.Sleep(SIMULATOR_MINE_DELAY)
+	}
+}
+
+// Bring up the simulator chain with its RPC and getwork servers and test wallets,
+// derodpkg cannot pass simulator mode to the chain so this stands in for it
+func startSimulator() (*blockchain.Blockchain, *rpc.RPCServer, error) {
+	globals.InitializeLog(os.Stdout, &lumberjack.Logger{
+		Filename:   daemonLogPath(),
+		MaxSize:    100,
+		MaxBackups: 2,
+	})
+	globals.Initialize()
+
+	globals.Logger.Info("[Netrunner] Starting simulator", "data_dir", globals.GetDataDirectory())
+
+	params := map[string]interface{}{"--simulator": true}
+
+	chain, err := blockchain.Blockchain_Start(params)
+	if err != nil {
+		return nil, nil, err
+	}
+	params["chain"] = chain
+
+	// No P2P, the simulator chain is never shared. The RPC server reads its bind once running,
+	// a wallet RPC must not swap it before then
+	argsLock.Lock()
+	server, err := rpc.RPCServer_Start(params)
+	if err != nil {
+		argsLock.Unlock()
+		chain.Shutdown()
+		return nil, nil, err
+	}
+
+	daemon := globals.Arguments["--rpc-bind"].(string)
+	if !waitListening(daemon) {
+		globals.Logger.Error(nil, "[Netrunner] Simulator RPC is not listening", "address", daemon)
+	}
+
+	// derod cannot stop its getwork server, once started it serves every later chain of this process
+	if !bw.ran {
+		replaceArguments(map[string]interface{}{"--getwork-bind": simulatorGetwork()})
+		go rpc.Getwork_server()
+		if !waitListening(simulatorGetwork()) {
+			globals.Logger.Error(nil, "[Netrunner] Simulator getwork is not listening", "address", simulatorGetwork())
+		}
+		bw.ran = true
+	}
+	argsLock.Unlock()
+
+	sim.active = true
+
+	if err := openSimulatorWallets(chain, daemon); err != nil {
+		globals.Logger.Error(err, "[Netrunner] Simulator wallets unavailable")
+	}
+
+	// getwork builds its templates for the integrator, the dev address is not registered here
+	if addr, err := globals.ParseValidateAddress(simulatorMiner()); err == nil {
+		chain.SetIntegratorAddress(*addr)
+	}
+
+	return chain, server, nil
+}
+
+// Open the test wallets, creating and registering them on a fresh chain
+func openSimulatorWallets(chain *blockchain.Blockchain, daemon string) (err error) {
+	if sim.genesis == nil {
+		if sim.genesis, err = openSimulatorWallet("wallet_genesis.db", simulatorGenesisSeed); err != nil {
+			return
+		}
+	}
+
+	fresh := chain.Get_Height() < 1
+
+	for i, seed := range simulatorSeeds {
+		if i >= len(sim.wallets) {
+			w, err := openSimulatorWallet(fmt.Sprintf("wallet_%d.db", i), seed)
+			if err != nil {
+				return err
+			}
+			sim.wallets = append(sim.wallets, w)
+		}
+
+		if fresh {
+			if err = chain.Add_TX_To_Pool(sim.wallets[i].GetRegistrationTX()); err != nil {
+				globals.Logger.Error(err, "[Netrunner] Simulator wallet not registered", "wallet", i)
+			}
+		}
+	}
+
+	connectWallets(daemon)
+
+	for i, w := range sim.wallets {
+		w.SetDaemonAddress(daemon)
+		w.SetOnlineMode()
+
+		bind := net.JoinHostPort(DEFAULT_DAEMON_LOCAL_ADDRESS, strconv.Itoa(SIMULATOR_WALLET_PORT+i))
+		server, err := startWalletRPC(w, fmt.Sprintf("wallet_%d", i), bind, "")
+		if err != nil {
+			globals.Logger.Error(err, "[Netrunner] Simulator wallet RPC not started", "wallet", i)
+			continue
+		}
+		sim.servers = append(sim.servers, server)
+	}
+
+	globals.Logger.Info("[Netrunner] Simulator wallets ready", "wallets", len(sim.wallets), "rpc", fmt.Sprintf("%d-%d", SIMULATOR_WALLET_PORT, SIMULATOR_WALLET_PORT+len(sim.wallets)-1))
+
+	return nil
+}
+
+func openSimulatorWallet(name string, seed string) (*walletapi.Wallet_Disk, error) {
+	file := filepath.Join(globals.GetDataDirectory(), name)
+
+	if _, err := os.Stat(file); err == nil {
+		return walletapi.Open_Encrypted_Wallet(file, "")
+	}
+
+	s, err := simulatorSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+
+	w, err := walletapi.Create_Encrypted_Wallet(file, "", s)
+	if err != nil {
+		return nil, err
+	}
+	w.SetNetwork(false)
+	w.Save_Wallet()
+
+	return w, nil
+}
+
+// Stop the wallet RPC servers, the miner, daemon RPC and chain are stopped by the caller
+func stopSimulator() {
+	if !sim.active {
+		return
+	}
+
+	var wg sync.WaitGroup
+	for _, s := range sim.servers {
+		wg.Add(1)
+		go func(s *walletrpc.RPCServer) {
+			defer wg.Done()
+			s.RPCServer_Stop()
+		}(s)
+	}
+	wg.Wait()
+
+	sim.active = false
+	sim.servers = nil
+	globals.Logger.Info("[Netrunner] Simulator stopped")
+}
+
+// Throw away the simulator chain and wallets, they are recreated on the next start
+func resetSimulator() error {
+	if bw.chain != nil {
+		return fmt.Errorf("daemon must be stopped to reset the simulator")
+	}
+
+	dir := globals.GetDataDirectory()
+	if !status.simulator || filepath.Base(dir) != "testnet_simulator" {
+		return fmt.Errorf("simulator is not selected")
+	}
+
+	if err := checkLock(dir); err != nil {
+		return err
+	}
+
+	// Closing waits on each wallet, do them together
+	var wg sync.WaitGroup
+	for _, w := range append(sim.wallets, sim.genesis) {
+		if w != nil {
+			wg.Add(1)
+			go func(w *walletapi.Wallet_Disk) {
+				defer wg.Done()
+				w.SetOfflineMode()
+				w.Close_Encrypted_Wallet()
+			}(w)
+		}
+	}
+	wg.Wait()
+	sim = Simulator{}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return err
+	}
+
+	globals.Logger.Info("[Netrunner] Simulator reset", "data_dir", dir)
+
+	return os.MkdirAll(dir, 0750)
+}
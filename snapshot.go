@@ -0,0 +1,373 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/config"
+	"github.com/deroproject/derohe/globals"
+)
+
+// A snapshot is a gzipped tar of a network chain directory, the manifest is
+// written as the last entry so checksums can be taken in a single pass
+type Manifest struct {
+	Version    int             `json:"version"`
+	Network    string          `json:"network"`
+	Height     int64           `json:"height"`
+	TopoHeight int64           `json:"topo_height"`
+	TopHash    string          `json:"top_hash"`
+	Created    time.Time       `json:"created"`
+	Netrunner  string          `json:"netrunner"`
+	Derohe     string          `json:"derohe"`
+	Files      []ManifestEntry `json:"files"`
+}
+
+type ManifestEntry struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+const (
+	SNAPSHOT_VERSION   = 1
+	SNAPSHOT_MANIFEST  = "manifest.json"
+	SNAPSHOT_EXTENSION = ".tar.gz"
+)
+
+// Counts bytes passing through for progress reporting
+type progressReader struct {
+	r        io.Reader
+	done     int64
+	total    int64
+	progress func(float64)
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.total > 0 {
+		p.progress(float64(p.done) / float64(p.total))
+	}
+	return n, err
+}
+
+// Last written topo record of the chain at dir
+func readTopTopo(dir string) (topo int64, height int64, blid string, err error) {
+	file, err := os.Open(filepath.Join(dir, "topo.map"))
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	count, err := storedTopoHeight(dir)
+	if err != nil || count < 1 {
+		return 0, 0, "", fmt.Errorf("chain is empty")
+	}
+
+	var buf [TOPORECORD_SIZE]byte
+	if _, err = file.ReadAt(buf[:], (count-1)*TOPORECORD_SIZE); err != nil {
+		return
+	}
+
+	topo = count - 1
+	height = int64(binary.LittleEndian.Uint64(buf[40:]))
+	blid = hex.EncodeToString(buf[:32])
+
+	return
+}
+
+func snapshotName() string {
+	network := "testnet"
+	if globals.IsMainnet() {
+		network = "mainnet"
+	}
+
+	return fmt.Sprintf("netrunner_%s_%s%s", network, time.Now().Format("20060102_1504"), SNAPSHOT_EXTENSION)
+}
+
+// Export the chain of the current network, a running daemon is paused for the duration
+func exportSnapshot(w io.Writer, progress func(float64)) (manifest Manifest, err error) {
+	dir := globals.GetDataDirectory()
+
+	if bw.chain != nil {
+		closeMiner()
+		status.paused = true
+		bw.chain.Lock()
+		globals.Logger.Info("[Netrunner] Daemon paused for snapshot export")
+
+		defer func() {
+			bw.chain.Unlock()
+			status.paused = false
+			globals.Logger.Info("[Netrunner] Daemon resumed")
+		}()
+	}
+
+	manifest.Version = SNAPSHOT_VERSION
+	manifest.Network = filepath.Base(dir)
+	manifest.Created = time.Now().UTC()
+	manifest.Netrunner = version.String()
+	manifest.Derohe = config.Version.String()
+
+	if manifest.TopoHeight, manifest.Height, manifest.TopHash, err = readTopTopo(dir); err != nil {
+		return
+	}
+
+	total := blockchain.DirSize(dir)
+	var done int64
+
+	gz, err := gzip.NewWriterLevel(w, gzip.BestSpeed)
+	if err != nil {
+		return
+	}
+	tw := tar.NewWriter(gz)
+
+	err = filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		// Lock files and leftovers of an interrupted prune are not part of the chain
+		if strings.HasSuffix(rel, ".lock") || strings.HasPrefix(rel, "balances_new/") {
+			return nil
+		}
+
+		file, err := os.Open(p)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+
+		if err = tw.WriteHeader(&tar.Header{Name: rel, Mode: 0600, Size: info.Size(), ModTime: info.ModTime()}); err != nil {
+			return err
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(tw, h), file)
+		if err != nil {
+			return err
+		}
+
+		manifest.Files = append(manifest.Files, ManifestEntry{Path: rel, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))})
+
+		done += n
+		if total > 0 {
+			progress(float64(done) / float64(total))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return
+	}
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return
+	}
+
+	if err = tw.WriteHeader(&tar.Header{Name: SNAPSHOT_MANIFEST, Mode: 0600, Size: int64(len(data)), ModTime: manifest.Created}); err != nil {
+		return
+	}
+
+	if _, err = tw.Write(data); err != nil {
+		return
+	}
+
+	if err = tw.Close(); err != nil {
+		return
+	}
+
+	if err = gz.Close(); err != nil {
+		return
+	}
+
+	progress(1)
+	globals.Logger.Info("[Netrunner] Snapshot exported", "network", manifest.Network, "height", manifest.Height, "top", manifest.TopHash, "files", len(manifest.Files))
+
+	return
+}
+
+// Bootstrap a chain from a snapshot, files are verified against the manifest before being put in place
+func importSnapshot(name string, progress func(float64)) (manifest Manifest, err error) {
+	if bw.chain != nil {
+		return manifest, fmt.Errorf("daemon must be stopped to bootstrap from a snapshot")
+	}
+
+	file, err := os.Open(name)
+	if err != nil {
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return
+	}
+
+	gz, err := gzip.NewReader(&progressReader{r: file, total: info.Size(), progress: progress})
+	if err != nil {
+		return manifest, fmt.Errorf("not a snapshot file: %w", err)
+	}
+	defer gz.Close()
+
+	base := dataDir()
+	if err = os.MkdirAll(base, 0750); err != nil {
+		return
+	}
+
+	tmp, err := os.MkdirTemp(base, "snapshot_")
+	if err != nil {
+		return
+	}
+	defer os.RemoveAll(tmp)
+
+	found := map[string]ManifestEntry{}
+	have_manifest := false
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return manifest, fmt.Errorf("snapshot is damaged: %w", err)
+		}
+
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if hdr.Name == SNAPSHOT_MANIFEST {
+			if err = json.NewDecoder(tr).Decode(&manifest); err != nil {
+				return manifest, fmt.Errorf("snapshot manifest is damaged: %w", err)
+			}
+			have_manifest = true
+			continue
+		}
+
+		// Refuse anything that would land outside the chain directory
+		clean := path.Clean(hdr.Name)
+		if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+			return manifest, fmt.Errorf("snapshot contains an invalid path %s", hdr.Name)
+		}
+
+		target := filepath.Join(tmp, filepath.FromSlash(clean))
+		if err = os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return manifest, err
+		}
+
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+		if err != nil {
+			return manifest, err
+		}
+
+		h := sha256.New()
+		n, err := io.Copy(io.MultiWriter(out, h), tr)
+		out.Close()
+		if err != nil {
+			return manifest, fmt.Errorf("snapshot is damaged: %w", err)
+		}
+
+		found[clean] = ManifestEntry{Path: clean, Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}
+	}
+
+	if !have_manifest {
+		return manifest, fmt.Errorf("snapshot has no manifest")
+	}
+
+	if err = verifyManifest(manifest, found); err != nil {
+		return
+	}
+
+	topo, height, top, err := readTopTopo(tmp)
+	if err != nil {
+		return
+	}
+
+	if topo != manifest.TopoHeight || height != manifest.Height || top != manifest.TopHash {
+		return manifest, fmt.Errorf("snapshot chain top does not match the manifest")
+	}
+
+	dst := filepath.Join(base, manifest.Network)
+	if err = checkLock(dst); err != nil {
+		return
+	}
+	if entries, err := os.ReadDir(dst); err == nil && len(entries) > 0 {
+		return manifest, fmt.Errorf("a %s chain already exists at %s", manifest.Network, dst)
+	}
+	os.Remove(dst)
+
+	if err = os.Rename(tmp, dst); err != nil {
+		return
+	}
+
+	progress(1)
+	globals.Logger.Info("[Netrunner] Bootstrapped from snapshot", "network", manifest.Network, "height", manifest.Height, "top", manifest.TopHash)
+
+	return
+}
+
+// Every file listed must be present with a matching size and checksum, and nothing else
+func verifyManifest(manifest Manifest, found map[string]ManifestEntry) error {
+	if manifest.Version != SNAPSHOT_VERSION {
+		return fmt.Errorf("unsupported snapshot version %d", manifest.Version)
+	}
+
+	if manifest.Network != "mainnet" && manifest.Network != "testnet" {
+		return fmt.Errorf("snapshot is for an unknown network %q", manifest.Network)
+	}
+
+	if len(manifest.Files) != len(found) {
+		return fmt.Errorf("snapshot holds %d files but the manifest lists %d", len(found), len(manifest.Files))
+	}
+
+	for _, f := range manifest.Files {
+		got, ok := found[f.Path]
+		if !ok {
+			return fmt.Errorf("snapshot is missing %s", f.Path)
+		}
+
+		if got.Size != f.Size || got.SHA256 != f.SHA256 {
+			return fmt.Errorf("checksum mismatch for %s", f.Path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,325 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"fyne.io/fyne/v2/dialog"
+	"github.com/deroproject/derohe/blockchain"
+	"github.com/deroproject/derohe/globals"
+)
+
+// Disk usage of the chain store, split the way derod lays it out on disk
+type DiskUsage struct {
+	balances int64
+	blocks   int64
+	topo     int64
+	other    int64
+	free     uint64
+}
+
+const (
+	DISK_WARN_BYTES  = 5 << 30 // warn the user below 5 GiB free
+	DISK_STOP_BYTES  = 1 << 30 // stop the daemon below 1 GiB free
+	DISK_CHECK_DELAY = 30 * time.Second
+	TOPORECORD_SIZE  = blockchain.TOPORECORD_SIZE
+	MIN_PRUNE_KEEP   = 50
+)
+
+// Network directories derod creates under the data dir
+var chainDirs = []string{"mainnet", "testnet"}
+
+// The base directory handed to derod, it appends the network name itself
+func dataDir() string {
+	if globals.Arguments["--data-dir"] != nil {
+		return globals.Arguments["--data-dir"].(string)
+	}
+
+	dir, err := os.Getwd()
+	if err != nil {
+		return os.TempDir()
+	}
+
+	return dir
+}
+
+// Walk the chain store of the current network and size its parts
+func getDiskUsage() (usage DiskUsage, err error) {
+	path := globals.GetDataDirectory()
+
+	entries, err := os.ReadDir(path)
+	if err != nil {
+		return
+	}
+
+	for _, e := range entries {
+		p := filepath.Join(path, e.Name())
+		switch e.Name() {
+		case "balances":
+			usage.balances = blockchain.DirSize(p)
+		case "bltx_store":
+			usage.blocks = blockchain.DirSize(p)
+		case "topo.map":
+			if info, err := e.Info(); err == nil {
+				usage.topo = info.Size()
+			}
+		default:
+			usage.other += blockchain.DirSize(p)
+		}
+	}
+
+	usage.free, err = diskFree(path)
+
+	return
+}
+
+func (u DiskUsage) total() int64 {
+	return u.balances + u.blocks + u.topo + u.other
+}
+
+// Count the written topo records of the chain at dir
+func storedTopoHeight(dir string) (int64, error) {
+	file, err := os.Open(filepath.Join(dir, "topo.map"))
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	var buf [TOPORECORD_SIZE]byte
+	count := info.Size() / TOPORECORD_SIZE
+	for ; count >= 1; count-- {
+		if _, err := file.ReadAt(buf[:], (count-1)*TOPORECORD_SIZE); err != nil {
+			return 0, err
+		}
+
+		clean := true
+		for _, b := range buf {
+			if b != 0 {
+				clean = false
+				break
+			}
+		}
+
+		if !clean {
+			break
+		}
+	}
+
+	return count, nil
+}
+
+// Discard history older than the last keep blocks, the daemon must not be running
+func pruneChain(keep int64) error {
+	if bw.chain != nil {
+		return fmt.Errorf("daemon must be stopped to prune")
+	}
+
+	if keep < MIN_PRUNE_KEEP {
+		return fmt.Errorf("at least %d blocks must be kept", MIN_PRUNE_KEEP)
+	}
+
+	topo, err := storedTopoHeight(globals.GetDataDirectory())
+	if err != nil {
+		return fmt.Errorf("no chain found to prune: %w", err)
+	}
+
+	if err = acquireLock(globals.GetDataDirectory()); err != nil {
+		return err
+	}
+	defer releaseLock()
+
+	if topo-keep < 1 {
+		return fmt.Errorf("chain is only %d blocks, nothing to prune", topo)
+	}
+
+	globals.Logger.Info("[Netrunner] Pruning blockchain history", "topo_height", topo-keep, "keep", keep)
+
+	if err = blockchain.Prune_Blockchain(topo - keep); err != nil {
+		return err
+	}
+
+	globals.Logger.Info("[Netrunner] Blockchain pruning successful")
+
+	return nil
+}
+
+// Whether a chain exists for any network under base
+func hasChain(base string) bool {
+	for _, n := range chainDirs {
+		if entries, err := os.ReadDir(filepath.Join(base, n)); err == nil && len(entries) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Move existing chain data to a new base directory, falling back to a copy across volumes
+func moveChain(from string, to string, progress func(float64)) error {
+	if bw.chain != nil {
+		return fmt.Errorf("daemon must be stopped to move the chain")
+	}
+
+	if filepath.Clean(from) == filepath.Clean(to) {
+		return nil
+	}
+
+	for _, n := range chainDirs {
+		if _, err := os.Stat(filepath.Join(to, n)); err == nil {
+			return fmt.Errorf("%s already holds a %s chain", to, n)
+		}
+
+		if err := checkLock(filepath.Join(from, n)); err != nil {
+			return err
+		}
+	}
+
+	var total, done int64
+	for _, n := range chainDirs {
+		total += blockchain.DirSize(filepath.Join(from, n))
+	}
+
+	for _, n := range chainDirs {
+		src := filepath.Join(from, n)
+		dst := filepath.Join(to, n)
+
+		if _, err := os.Stat(src); os.IsNotExist(err) {
+			continue
+		}
+
+		if err := os.MkdirAll(to, 0750); err != nil {
+			return err
+		}
+
+		if err := os.Rename(src, dst); err == nil {
+			done += blockchain.DirSize(dst)
+			if total > 0 {
+				progress(float64(done) / float64(total))
+			}
+			continue
+		}
+
+		// Different volume, copy then remove the original
+		err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+
+			rel, err := filepath.Rel(src, path)
+			if err != nil {
+				return err
+			}
+
+			target := filepath.Join(dst, rel)
+			if info.IsDir() {
+				return os.MkdirAll(target, 0750)
+			}
+
+			if err = copyFile(path, target, info.Mode()); err != nil {
+				return err
+			}
+
+			done += info.Size()
+			if total > 0 {
+				progress(float64(done) / float64(total))
+			}
+
+			return nil
+		})
+
+		if err != nil {
+			os.RemoveAll(dst)
+			return err
+		}
+
+		if err = os.RemoveAll(src); err != nil {
+			return err
+		}
+	}
+
+	progress(1)
+	globals.Logger.Info("[Netrunner] Chain data moved", "from", from, "to", to)
+
+	return nil
+}
+
+func copyFile(src string, dst string, mode os.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_EXCL|os.O_WRONLY, mode)
+	if err != nil {
+		return err
+	}
+
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+
+	if err = out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+
+	return out.Close()
+}
+
+// Routine to watch free space while the daemon runs, it is stopped before the disk fills
+func watchDisk() {
+	warned := false
+
+	for bw.chain != nil {
+		free, err := diskFree(globals.GetDataDirectory())
+		if err != nil {
+			globals.Logger.V(1).Info("[Netrunner] Free space check unavailable", "err", err)
+			return
+		}
+
+		status.disk_free = free
+
+		if free < DISK_STOP_BYTES {
+			globals.Logger.Error(nil, "[Netrunner] Disk almost full, stopping daemon", "free", blockchain.ByteCountIEC(int64(free)))
+			closeMiner()
+			closeDaemon()
+			dialog.ShowError(fmt.Errorf("Only %s free on the data directory volume, the daemon was stopped to protect the chain database.", blockchain.ByteCountIEC(int64(free))), a.window)
+			return
+		}
+
+		if free < DISK_WARN_BYTES && !warned {
+			warned = true
+			globals.Logger.Info("[Netrunner] Disk space is running low", "free", blockchain.ByteCountIEC(int64(free)))
+			dialog.ShowInformation("Low Disk Space", fmt.Sprintf("Only %s free on the data directory volume.\nThe daemon will stop below %s.", blockchain.ByteCountIEC(int64(free)), blockchain.ByteCountIEC(DISK_STOP_BYTES)), a.window)
+		} else if free >= DISK_WARN_BYTES {
+			warned = false
+		}
+
+		time.Sleep(DISK_CHECK_DELAY)
+	}
+}
@@ -0,0 +1,198 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"fyne.io/fyne/v2"
+	"fyne.io/fyne/v2/driver/desktop"
+	"github.com/deroproject/derohe/globals"
+)
+
+// Tray menu items refreshed with the node state
+type Tray struct {
+	menu   *fyne.Menu
+	show   *fyne.MenuItem
+	height *fyne.MenuItem
+	sync   *fyne.MenuItem
+	rate   *fyne.MenuItem
+	daemon *fyne.MenuItem
+	miner  *fyne.MenuItem
+	active bool
+}
+
+const (
+	CLOSE_QUITS   = "Quit"
+	CLOSE_TO_TRAY = "Tray"
+	TRAY_UPDATE   = 2 * time.Second
+)
+
+var tray Tray
+
+// Set up the tray icon and menu where the desktop driver supports it
+func setupTray() {
+	desk, ok := a.app.(desktop.App)
+	if !ok {
+		globals.Logger.V(1).Info("[Netrunner] System tray is not available")
+		return
+	}
+
+	tray.show = fyne.NewMenuItem("Hide Netrunner", toggleWindow)
+	tray.height = fyne.NewMenuItem("Height  ---", nil)
+	tray.height.Disabled = true
+	tray.sync = fyne.NewMenuItem("Daemon  Offline", nil)
+	tray.sync.Disabled = true
+	tray.rate = fyne.NewMenuItem("Hashrate  ---", nil)
+	tray.rate.Disabled = true
+
+	tray.daemon = fyne.NewMenuItem("Start Daemon", func() {
+		if bw.chain == nil {
+			if runDaemon != nil {
+				a.window.Show()
+				runDaemon()
+			}
+		} else {
+			go stopDaemon()
+		}
+		updateTray()
+	})
+
+	tray.miner = fyne.NewMenuItem("Start Mining", func() {
+		if m.Mission == 1 {
+			closeMiner()
+		} else if runMiner != nil && synced() {
+			runMiner()
+		}
+		updateTray()
+	})
+	tray.miner.Disabled = true
+
+	quit := fyne.NewMenuItem("Quit", appClose)
+	quit.IsQuit = true
+
+	tray.menu = fyne.NewMenu("Netrunner",
+		tray.show,
+		fyne.NewMenuItemSeparator(),
+		tray.height,
+		tray.sync,
+		tray.rate,
+		fyne.NewMenuItemSeparator(),
+		tray.daemon,
+		tray.miner,
+		fyne.NewMenuItemSeparator(),
+		quit,
+	)
+
+	desk.SetSystemTrayIcon(resourceIconPng)
+	desk.SetSystemTrayMenu(tray.menu)
+	tray.active = true
+
+	go func() {
+		for {
+			updateTray()
+			time.Sleep(TRAY_UPDATE)
+		}
+	}()
+}
+
+// The window close button either quits or hides to the tray
+func closeWindow() {
+	if tray.active && status.close_action == CLOSE_TO_TRAY && started {
+		a.window.Hide()
+		status.hidden = true
+		updateTray()
+		return
+	}
+
+	appClose()
+}
+
+func toggleWindow() {
+	if status.hidden {
+		a.window.Show()
+		a.window.RequestFocus()
+		status.hidden = false
+	} else {
+		a.window.Hide()
+		status.hidden = true
+	}
+	updateTray()
+}
+
+// The daemon is running and has caught up with the best peer
+func synced() bool {
+	if bw.chain == nil || status.paused {
+		return false
+	}
+
+	peerHeight := networkHeight(bw.chain)
+
+	return peerHeight > 0 && bw.chain.Get_Height() >= peerHeight
+}
+
+func updateTray() {
+	if !tray.active {
+		return
+	}
+
+	if status.hidden {
+		tray.show.Label = "Show Netrunner"
+	} else {
+		tray.show.Label = "Hide Netrunner"
+	}
+
+	if chain := bw.chain; chain != nil {
+		peerHeight := networkHeight(chain)
+		tray.height.Label = fmt.Sprintf("Height  %d / %d", chain.Get_Height(), peerHeight)
+
+		switch {
+		case status.paused:
+			tray.sync.Label = "Daemon  Paused"
+		case synced():
+			tray.sync.Label = "Daemon  Running"
+		case peerHeight > 0:
+			tray.sync.Label = fmt.Sprintf("Daemon  Syncing %.2f%%", float64(chain.Get_Height())/float64(peerHeight)*100)
+		default:
+			tray.sync.Label = "Daemon  Waiting for peers"
+		}
+
+		tray.daemon.Label = "Stop Daemon"
+	} else {
+		tray.height.Label = "Height  ---"
+		tray.sync.Label = "Daemon  Offline"
+		tray.daemon.Label = "Start Daemon"
+	}
+
+	if m.Mission == 1 && m.Hashrate != "" {
+		tray.rate.Label = "Hashrate  " + m.Hashrate
+	} else if m.Mission == 1 {
+		tray.rate.Label = "Hashrate  Starting"
+	} else {
+		tray.rate.Label = "Hashrate  ---"
+	}
+
+	if m.Mission == 1 {
+		tray.miner.Label = "Stop Mining"
+		tray.miner.Disabled = false
+	} else {
+		tray.miner.Label = "Start Mining"
+		tray.miner.Disabled = !synced()
+	}
+
+	tray.menu.Refresh()
+}
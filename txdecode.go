@@ -0,0 +1,188 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/deroproject/derohe/cmd/derod/rpc"
+	"github.com/deroproject/derohe/cryptography/crypto"
+	derorpc "github.com/deroproject/derohe/rpc"
+	"github.com/deroproject/derohe/transaction"
+)
+
+// A transaction found on the local node, decoded for inspection
+type TxDetail struct {
+	hash       string
+	kind       string
+	version    uint64
+	height     int64 // block height, -1 while in a pool
+	topo       int64
+	block      string
+	invalid    []string // blocks that carry it without applying it
+	pool       string
+	fees       uint64
+	value      uint64
+	size       int
+	proof_size int
+	signer     string
+	payloads   []TxPayload
+	sc_args    []string
+	code       string
+	raw        string
+}
+
+type TxPayload struct {
+	scid       string
+	burn       uint64
+	ring_size  int
+	ring       []string
+	fees       uint64
+	rpc_size   int
+	proof_size int
+}
+
+// Decode a tx from the chain store or the pools, the daemon's GetTransaction fills in ring members
+func decodeTransaction(hash string) (d TxDetail, err error) {
+	if bw.chain == nil {
+		return d, fmt.Errorf("daemon is not running")
+	}
+
+	hash = strings.ToLower(strings.TrimSpace(hash))
+	if b, err := hex.DecodeString(hash); err != nil || len(b) != 32 {
+		return d, fmt.Errorf("transaction hash must be 64 hex characters")
+	}
+
+	result, err := rpc.GetTransaction(context.Background(), derorpc.GetTransaction_Params{Tx_Hashes: []string{hash}})
+	if err != nil {
+		return
+	}
+
+	var raw []byte
+	var related derorpc.Tx_Related_Info
+	if len(result.Txs_as_hex) == 1 && result.Txs_as_hex[0] != "" {
+		related = result.Txs[0]
+		if raw, err = hex.DecodeString(result.Txs_as_hex[0]); err != nil {
+			return
+		}
+	} else if tx := bw.chain.Regpool.Regpool_Get_TX(crypto.HashHexToHash(hash)); tx != nil {
+		// Registrations wait in their own pool
+		raw = tx.Serialize()
+		related = derorpc.Tx_Related_Info{Block_Height: -1, In_pool: true}
+		d.pool = "registration pool"
+	} else {
+		return d, fmt.Errorf("transaction %s is not known to the local node", hash)
+	}
+
+	var tx transaction.Transaction
+	if err = tx.Deserialize(raw); err != nil {
+		return d, fmt.Errorf("transaction could not be decoded: %w", err)
+	}
+
+	d.hash = hash
+	d.kind = txKind(&tx)
+	d.version = tx.Version
+	d.value = tx.Value
+	d.size = len(raw)
+	d.raw = hex.EncodeToString(raw)
+	d.signer = related.Signer
+	d.code = related.Code
+	d.invalid = related.InvalidBlock
+	d.height, d.topo = -1, -1
+
+	if related.In_pool {
+		if d.pool == "" {
+			d.pool = "mempool"
+		}
+	} else if related.ValidBlock != "" {
+		d.block = related.ValidBlock
+		d.topo = related.Block_Height
+		if bl, err := bw.chain.Load_BL_FROM_ID(crypto.HashHexToHash(related.ValidBlock)); err == nil {
+			d.height = int64(bl.Height)
+		}
+	}
+
+	if !tx.IsRegistration() && !tx.IsCoinbase() && !tx.IsPremine() {
+		d.fees = tx.Fees()
+	}
+
+	for i, p := range tx.Payloads {
+		payload := TxPayload{
+			burn:      p.BurnValue,
+			ring_size: int(p.Statement.RingSize),
+			fees:      p.Statement.Fees,
+			rpc_size:  len(p.RPCPayload),
+		}
+
+		if p.SCID != crypto.ZEROHASH {
+			payload.scid = p.SCID.String()
+		}
+
+		if p.Proof != nil {
+			var buf bytes.Buffer
+			p.Proof.Serialize(&buf)
+			payload.proof_size = buf.Len()
+			d.proof_size += buf.Len()
+		}
+
+		if i < len(related.Ring) {
+			payload.ring = related.Ring[i]
+		}
+
+		d.payloads = append(d.payloads, payload)
+	}
+
+	for _, arg := range tx.SCDATA {
+		// Installed code is shown on its own
+		if arg.Name == derorpc.SCCODE {
+			if d.code == "" {
+				d.code, _ = arg.Value.(string)
+			}
+			continue
+		}
+		d.sc_args = append(d.sc_args, scArgument(arg))
+	}
+
+	return d, nil
+}
+
+func txKind(tx *transaction.Transaction) string {
+	if tx.TransactionType != transaction.SC_TX {
+		return tx.TransactionType.String()
+	}
+
+	if tx.SCDATA.Has(derorpc.SCACTION, derorpc.DataUint64) && derorpc.SC_ACTION(tx.SCDATA.Value(derorpc.SCACTION, derorpc.DataUint64).(uint64)) == derorpc.SC_INSTALL {
+		return "SC INSTALL"
+	}
+
+	return "SC INVOKE"
+}
+
+func scArgument(arg derorpc.Argument) string {
+	switch v := arg.Value.(type) {
+	case string:
+		return fmt.Sprintf("%s (%s) %q", arg.Name, arg.DataType, v)
+	case crypto.Hash:
+		return fmt.Sprintf("%s (%s) %s", arg.Name, arg.DataType, v.String())
+	case derorpc.SC_ACTION:
+		return fmt.Sprintf("%s (%s) %d", arg.Name, arg.DataType, v)
+	}
+
+	return fmt.Sprintf("%s (%s) %v", arg.Name, arg.DataType, arg.Value)
+}
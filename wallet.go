@@ -0,0 +1,310 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/deroproject/derohe/cryptography/crypto"
+	"github.com/deroproject/derohe/globals"
+	derorpc "github.com/deroproject/derohe/rpc"
+	"github.com/deroproject/derohe/walletapi"
+	walletrpc "github.com/deroproject/derohe/walletapi/rpcserver"
+)
+
+// A wallet file opened in Netrunner. DERO balances are encrypted to the account key, so even
+// reading them takes the full wallet with its spend key, there is no view-only wallet in DERO.
+// What is done with the wallet is up to whoever holds the slot
+type WalletSlot struct {
+	sync.Mutex
+	busy   sync.RWMutex // read held while a transaction is built and sent, close waits for it
+	wallet *walletapi.Wallet_Disk
+	file   string
+	name   string
+}
+
+// A reward paid to an address by a block on the local chain
+type Reward struct {
+	height     int64
+	topo       int64
+	amount     uint64
+	miniblocks int  // miniblocks of the block found by the address
+	integrator bool // the address integrated the block and got the leftover
+	side       bool
+}
+
+const REWARDS_SHOWN = 100
+
+// Full wallet of the mining address, only read to show its balance and transfers, Netrunner
+// never builds or signs a transaction with it
+var miningWallet = WalletSlot{name: "Mining wallet"}
+
+// The address rewards are paid to, the integrator address when set or the one derod picked
+func miningAddress() (*derorpc.Address, error) {
+	if status.integrator != "" {
+		return globals.ParseValidateAddress(status.integrator)
+	}
+
+	if chain := bw.chain; chain != nil {
+		addr := chain.IntegratorAddress()
+		return &addr, nil
+	}
+
+	return nil, fmt.Errorf("no mining address is set")
+}
+
+// Open the wallet file of the mining address, any other wallet is refused as the panel
+// only reports what the mining address earned
+func openMiningWallet(file string, password string) error {
+	addr, err := miningAddress()
+	if err != nil {
+		return err
+	}
+
+	return miningWallet.open(file, password, func(w *walletapi.Wallet_Disk) error {
+		if w.GetAddress().String() != addr.String() {
+			return fmt.Errorf("wallet is for %s, not the mining address", w.GetAddress().String())
+		}
+		return nil
+	})
+}
+
+// Open a wallet file into the slot, closing the one held before. check may refuse the wallet
+func (s *WalletSlot) open(file string, password string, check func(*walletapi.Wallet_Disk) error) error {
+	w, err := walletapi.Open_Encrypted_Wallet(file, password)
+	if err != nil {
+		return err
+	}
+
+	if check != nil {
+		if err = check(w); err != nil {
+			go w.Close_Encrypted_Wallet()
+			return err
+		}
+	}
+
+	s.close()
+
+	s.Lock()
+	s.wallet = w
+	s.file = file
+	s.Unlock()
+
+	w.SetNetwork(globals.IsMainnet())
+	s.connect()
+
+	globals.Logger.Info("[Netrunner] "+s.name+" opened", "address", w.GetAddress().String())
+
+	return nil
+}
+
+// Serve a wallet over RPC. The server takes its login from --rpc-login when started and its
+// bind from --rpc-bind once running, the daemon's values are put back after it listens
+func startWalletRPC(w *walletapi.Wallet_Disk, title string, bind string, login string) (*walletrpc.RPCServer, error) {
+	argsLock.Lock()
+	defer argsLock.Unlock()
+
+	daemon := map[string]interface{}{
+		"--rpc-bind":  globals.Arguments["--rpc-bind"],
+		"--rpc-login": globals.Arguments["--rpc-login"],
+	}
+	defer replaceArguments(daemon)
+
+	wallet := map[string]interface{}{"--rpc-bind": bind, "--rpc-login": nil}
+	if login != "" {
+		wallet["--rpc-login"] = login
+	}
+	replaceArguments(wallet)
+
+	server, err := walletrpc.RPCServer_Start(w, title)
+	if err != nil {
+		return nil, err
+	}
+
+	if !waitListening(bind) {
+		server.RPCServer_Stop()
+		return nil, fmt.Errorf("wallet RPC is not listening on %s", bind)
+	}
+
+	return server, nil
+}
+
+// Point the wallet at the embedded daemon, it stays offline while the daemon is stopped
+func (s *WalletSlot) connect() {
+	s.Lock()
+	defer s.Unlock()
+
+	if s.wallet == nil || bw.chain == nil {
+		return
+	}
+
+	connectWallets(bw.rpc)
+	s.wallet.SetDaemonAddress(bw.rpc)
+	s.wallet.SetOnlineMode()
+}
+
+func (s *WalletSlot) close() {
+	s.Lock()
+	w := s.wallet
+	s.wallet = nil
+	s.file = ""
+	s.Unlock()
+
+	if w != nil {
+		s.busy.Lock()
+		w.Close_Encrypted_Wallet()
+		s.busy.Unlock()
+		globals.Logger.Info("[Netrunner] " + s.name + " closed")
+	}
+}
+
+// The wallet held, nil when the slot is empty
+func (s *WalletSlot) get() *walletapi.Wallet_Disk {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.wallet
+}
+
+// The wallet held for building and sending a transaction, it is not closed before release.
+// nil when the slot is empty, there is nothing to release then
+func (s *WalletSlot) acquire() *walletapi.Wallet_Disk {
+	s.busy.RLock()
+
+	w := s.get()
+	if w == nil {
+		s.busy.RUnlock()
+	}
+
+	return w
+}
+
+func (s *WalletSlot) release() {
+	s.busy.RUnlock()
+}
+
+// Wallet the dApp gateway and the contracts panel send from, any wallet may be held here and
+// each transaction is confirmed by the user before it is signed
+var dappWallet = WalletSlot{name: "dApp wallet"}
+
+// The gateway backend serves the wallet held before, it is started again on the next request
+func openDappWallet(file string, password string) error {
+	stopGatewayBackend()
+	return dappWallet.open(file, password, nil)
+}
+
+func closeDappWallet() {
+	stopGatewayBackend()
+	dappWallet.close()
+}
+
+// Point every open wallet at the embedded daemon once it runs
+func connectOpenWallets() {
+	miningWallet.connect()
+	dappWallet.connect()
+}
+
+func closeWallets() {
+	closeDappWallet()
+	miningWallet.close()
+}
+
+// Balance and history of the wallet held, ok is false when the slot is empty
+func (s *WalletSlot) state() (mature uint64, locked uint64, height uint64, registered bool, online bool, transfers []derorpc.Entry, ok bool) {
+	s.Lock()
+	defer s.Unlock()
+
+	w := s.wallet
+	if w == nil {
+		return
+	}
+
+	mature, locked = w.Get_Balance()
+	height = w.Get_Height()
+	registered = w.IsRegistered()
+	online = w.IsDaemonOnlineCached()
+	transfers = w.Show_Transfers(crypto.ZEROHASH, true, true, true, 0, 0, "", "", 0, 0)
+
+	// Newest first
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return transfers[i].TopoHeight > transfers[j].TopoHeight
+	})
+
+	return mature, locked, height, registered, online, transfers, true
+}
+
+// Address of a compressed key as found in a block's miner tx
+func integratorAddress(key [33]byte) (string, error) {
+	addr, err := derorpc.NewAddressFromCompressedKeys(key[:])
+	if err != nil {
+		return "", err
+	}
+	addr.Mainnet = globals.IsMainnet()
+
+	return addr.String(), nil
+}
+
+// Rewards paid to a compressed key by the blocks sampled, the reward of a block is split
+// evenly over its miniblocks and the integrator also gets what does not divide
+func addressRewards(samples []BlockSample, key [33]byte) (rewards []Reward) {
+	hash := keyHash(key)
+
+	for _, s := range samples {
+		if s.miniblocks < 1 {
+			continue
+		}
+
+		share := s.reward / uint64(s.miniblocks)
+		r := Reward{height: s.height, topo: s.topo, side: s.side}
+
+		if s.integrator == key {
+			r.integrator = true
+			r.miniblocks++
+			r.amount += s.reward - share*uint64(s.miniblocks-1)
+		}
+
+		for _, k := range s.keys {
+			if k == hash {
+				r.miniblocks++
+				r.amount += share
+			}
+		}
+
+		if r.miniblocks > 0 {
+			rewards = append(rewards, r)
+		}
+	}
+
+	return
+}
+
+// Rewards to an address over the sampled blocks newest first, with the number of blocks scanned
+func (a *Analytics) rewards(addr *derorpc.Address) ([]Reward, int) {
+	var key [33]byte
+	copy(key[:], addr.PublicKey.EncodeCompressed())
+
+	a.Lock()
+	rewards := addressRewards(a.samples, key)
+	scanned := len(a.samples)
+	a.Unlock()
+
+	for i, j := 0, len(rewards)-1; i < j; i, j = i+1, j-1 {
+		rewards[i], rewards[j] = rewards[j], rewards[i]
+	}
+
+	return rewards, scanned
+}
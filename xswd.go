@@ -0,0 +1,859 @@
+// Netrunner
+// Copyright 2021-2023 DERO Foundation. All rights reserved.
+// Use of this source code in any form is governed by RESEARCH license.
+// license can be found in the LICENSE file.
+//
+// THIS SOFTWARE IS PROVIDED BY THE COPYRIGHT HOLDERS AND CONTRIBUTORS "AS IS" AND ANY
+// EXPRESS OR IMPLIED WARRANTIES, INCLUDING, BUT NOT LIMITED TO, THE IMPLIED WARRANTIES OF
+// MERCHANTABILITY AND FITNESS FOR A PARTICULAR PURPOSE ARE DISCLAIMED. IN NO EVENT SHALL
+// THE COPYRIGHT HOLDER OR CONTRIBUTORS BE LIABLE FOR ANY DIRECT, INDIRECT, INCIDENTAL,
+// SPECIAL, EXEMPLARY, OR CONSEQUENTIAL DAMAGES (INCLUDING, BUT NOT LIMITED TO,
+// PROCUREMENT OF SUBSTITUTE GOODS OR SERVICES; LOSS OF USE, DATA, OR PROFITS; OR BUSINESS
+// INTERRUPTION) HOWEVER CAUSED AND ON ANY THEORY OF LIABILITY, WHETHER IN CONTRACT,
+// STRICT LIABILITY, OR TORT (INCLUDING NEGLIGENCE OR OTHERWISE) ARISING IN ANY WAY OUT OF
+// THE USE OF THIS SOFTWARE, EVEN IF ADVISED OF THE POSSIBILITY OF SUCH DAMAGE.
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/deroproject/derohe/globals"
+	derorpc "github.com/deroproject/derohe/rpc"
+	"github.com/deroproject/derohe/walletapi"
+	walletrpc "github.com/deroproject/derohe/walletapi/rpcserver"
+	"github.com/gorilla/websocket"
+)
+
+// Local websocket gateway speaking the XSWD wallet connection protocol. An application
+// sends its ApplicationData first and is accepted or refused by the user, after that it
+// sends JSON-RPC requests. Daemon methods carry the DERO. prefix and go to the embedded
+// daemon, wallet methods need the user's permission and go to the open wallet
+type Gateway struct {
+	sync.Mutex
+	server  *http.Server
+	backend *walletrpc.RPCServer
+	served  *walletapi.Wallet_Disk // wallet the backend was started on
+	address string                 // wallet RPC the gateway forwards to, loopback only
+	login   string
+	apps    map[string]*XSWDApp
+	conns   map[string]bool
+	audit   []AuditEntry
+	prompt  func(PermissionRequest) Permission
+}
+
+// Registered application, its stored permissions apply on every connection
+type XSWDApp struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	URL         string                `json:"url"`
+	Permissions map[string]Permission `json:"permissions"`
+	Signer      string                `json:"signer,omitempty"` // address that signed the id when registered
+	Added       time.Time             `json:"added"`
+	LastSeen    time.Time             `json:"last_seen"`
+}
+
+// First message of a connection
+type ApplicationData struct {
+	ID          string                `json:"id"`
+	Name        string                `json:"name"`
+	Description string                `json:"description"`
+	URL         string                `json:"url"`
+	Permissions map[string]Permission `json:"permissions"`
+	Signature   []byte                `json:"signature"` // the id signed by a DERO wallet, as SignData writes it
+}
+
+type AuthorizationResponse struct {
+	Message  string `json:"message"`
+	Accepted bool   `json:"accepted"`
+}
+
+type Permission int
+
+const (
+	PERMISSION_ASK Permission = iota
+	PERMISSION_ALLOW
+	PERMISSION_DENY
+	PERMISSION_ALWAYS_ALLOW
+	PERMISSION_ALWAYS_DENY
+)
+
+// What the user is asked, method is empty for the connection itself
+type PermissionRequest struct {
+	app      ApplicationData
+	method   string
+	params   string // in full, a spend is listed transfer by transfer
+	always   bool   // whether the answer may be remembered
+	verified bool   // the application signed its data, an origin is no proof
+}
+
+type AuditEntry struct {
+	Time     time.Time `json:"time"`
+	App      string    `json:"app"`
+	ID       string    `json:"id"`
+	Method   string    `json:"method"`
+	Decision string    `json:"decision"`
+	Error    string    `json:"error,omitempty"`
+}
+
+type xswdRequest struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+const (
+	XSWD_PORT           = 44326
+	XSWD_PATH           = "/xswd"
+	XSWD_APPS_FILE      = "apps.json"
+	XSWD_AUDIT_FILE     = "xswd_audit.log"
+	XSWD_AUDIT_SHOWN    = 200
+	XSWD_MAX_MESSAGE    = 1 << 20
+	XSWD_PROMPT_TIMEOUT = 2 * time.Minute
+)
+
+// Wallet methods an application may call, the derohe wallet RPC names
+var xswdWalletMethods = map[string]bool{
+	"Echo":                   true,
+	"GetAddress":             true,
+	"GetBalance":             true,
+	"GetHeight":              true,
+	"GetTransferbyTXID":      true,
+	"GetTransfers":           true,
+	"MakeIntegratedAddress":  true,
+	"SplitIntegratedAddress": true,
+	"QueryKey":               true,
+	"transfer":               true,
+	"Transfer":               true,
+	"scinvoke":               true,
+}
+
+// Spending and key export are confirmed every time
+var xswdAlwaysAsk = map[string]bool{
+	"QueryKey": true,
+	"transfer": true,
+	"Transfer": true,
+	"scinvoke": true,
+}
+
+var gateway = Gateway{apps: map[string]*XSWDApp{}, conns: map[string]bool{}}
+
+// Applications outside a browser send no origin, pages must come from a web origin
+var xswdUpgrader = websocket.Upgrader{CheckOrigin: func(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	u, err := url.Parse(origin)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}}
+
+var xswdClient = &http.Client{Timeout: time.Minute}
+
+func (p Permission) String() string {
+	switch p {
+	case PERMISSION_ALLOW:
+		return "allow"
+	case PERMISSION_DENY:
+		return "deny"
+	case PERMISSION_ALWAYS_ALLOW:
+		return "always allow"
+	case PERMISSION_ALWAYS_DENY:
+		return "always deny"
+	}
+
+	return "ask"
+}
+
+func (p Permission) allowed() bool {
+	return p == PERMISSION_ALLOW || p == PERMISSION_ALWAYS_ALLOW
+}
+
+func xswdPath(name string) (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "Netrunner", name), nil
+}
+
+// Load the registry, a missing file means no application was registered yet
+func (g *Gateway) load() error {
+	path, err := xswdPath(XSWD_APPS_FILE)
+	if err != nil {
+		return err
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	apps := map[string]*XSWDApp{}
+	if err = json.Unmarshal(data, &apps); err != nil {
+		return err
+	}
+
+	g.Lock()
+	g.apps = apps
+	g.Unlock()
+
+	return nil
+}
+
+// Caller holds the lock
+func (g *Gateway) save() error {
+	path, err := xswdPath(XSWD_APPS_FILE)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(g.apps, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, data, 0600)
+}
+
+// Record a decision in memory for the panel and on disk for later review
+func (g *Gateway) record(e AuditEntry) {
+	e.Time = time.Now()
+
+	g.Lock()
+	g.audit = append(g.audit, e)
+	if len(g.audit) > XSWD_AUDIT_SHOWN {
+		g.audit = g.audit[len(g.audit)-XSWD_AUDIT_SHOWN:]
+	}
+	g.Unlock()
+
+	path, err := xswdPath(XSWD_AUDIT_FILE)
+	if err != nil {
+		return
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		globals.Logger.V(1).Error(err, "[Netrunner] Could not write gateway audit log", "path", path)
+		return
+	}
+	defer file.Close()
+
+	json.NewEncoder(file).Encode(e)
+}
+
+func (g *Gateway) auditLog() []AuditEntry {
+	g.Lock()
+	defer g.Unlock()
+
+	return append([]AuditEntry(nil), g.audit...)
+}
+
+// Registered applications ordered by name
+func (g *Gateway) registry() (apps []XSWDApp) {
+	g.Lock()
+	defer g.Unlock()
+
+	for _, app := range g.apps {
+		apps = append(apps, *app)
+	}
+
+	sortApps(apps)
+
+	return
+}
+
+func sortApps(apps []XSWDApp) {
+	for i := 1; i < len(apps); i++ {
+		for j := i; j > 0 && strings.ToLower(apps[j].Name) < strings.ToLower(apps[j-1].Name); j-- {
+			apps[j], apps[j-1] = apps[j-1], apps[j]
+		}
+	}
+}
+
+// Forget an application and everything it was allowed
+func (g *Gateway) revoke(id string) {
+	g.Lock()
+	defer g.Unlock()
+
+	if app, ok := g.apps[id]; ok {
+		delete(g.apps, id)
+		if err := g.save(); err != nil {
+			globals.Logger.Error(err, "[Netrunner] Could not save gateway registry")
+		}
+		globals.Logger.Info("[Netrunner] dApp revoked", "app", app.Name, "id", app.ID)
+	}
+}
+
+func (g *Gateway) setPrompt(prompt func(PermissionRequest) Permission) {
+	g.Lock()
+	g.prompt = prompt
+	g.Unlock()
+}
+
+func (g *Gateway) running() bool {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.server != nil
+}
+
+// Listen for applications on loopback
+func startGateway() error {
+	if err := gateway.load(); err != nil {
+		globals.Logger.Error(err, "[Netrunner] Could not load gateway registry")
+	}
+
+	gateway.Lock()
+	defer gateway.Unlock()
+
+	if gateway.server != nil {
+		return nil
+	}
+
+	l, err := net.Listen("tcp", net.JoinHostPort(DEFAULT_DAEMON_LOCAL_ADDRESS, fmt.Sprint(XSWD_PORT)))
+	if err != nil {
+		return fmt.Errorf("could not start dApp gateway: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(XSWD_PATH, gateway.serveWS)
+	gateway.server = &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	go func(s *http.Server) {
+		if err := s.Serve(l); err != http.ErrServerClosed {
+			globals.Logger.Error(err, "[Netrunner] dApp gateway stopped")
+		}
+	}(gateway.server)
+
+	globals.Logger.Info("[Netrunner] dApp gateway listening", "address", l.Addr().String()+XSWD_PATH)
+
+	return nil
+}
+
+func startupGateway() error {
+	if !status.gateway {
+		return nil
+	}
+
+	return startGateway()
+}
+
+func stopGateway() {
+	gateway.Lock()
+	s := gateway.server
+	gateway.server = nil
+	gateway.Unlock()
+
+	if s != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		s.Shutdown(ctx)
+		globals.Logger.Info("[Netrunner] dApp gateway stopped")
+	}
+
+	stopGatewayBackend()
+}
+
+// The wallet RPC server counts as a derod subsystem, it must be gone before the chain closes
+func stopGatewayBackend() {
+	gateway.Lock()
+	backend := gateway.backend
+	gateway.backend = nil
+	gateway.served = nil
+	gateway.address = ""
+	gateway.Unlock()
+
+	if backend != nil {
+		backend.RPCServer_Stop()
+	}
+}
+
+// Serve the wallet on a private loopback port, started on first use and again when the
+// dApp wallet was swapped
+func (g *Gateway) walletBackend(w *walletapi.Wallet_Disk) (address string, login string, err error) {
+	g.Lock()
+	defer g.Unlock()
+
+	if g.backend != nil && g.served == w {
+		return g.address, g.login, nil
+	}
+
+	if g.backend != nil {
+		g.backend.RPCServer_Stop()
+		g.backend, g.served, g.address = nil, nil, ""
+	}
+
+	if bw.chain == nil {
+		return "", "", fmt.Errorf("daemon is not running")
+	}
+
+	bind, err := freeLocalAddress()
+	if err != nil {
+		return
+	}
+
+	secret := make([]byte, 16)
+	if _, err = rand.Read(secret); err != nil {
+		return
+	}
+	login = "xswd:" + hex.EncodeToString(secret)
+
+	if g.backend, err = startWalletRPC(w, "xswd", bind, login); err != nil {
+		return
+	}
+	g.served, g.address, g.login = w, bind, login
+
+	return bind, login, nil
+}
+
+func validApplication(app ApplicationData, origin string) error {
+	if len(app.ID) != 64 {
+		return fmt.Errorf("application id must be 64 hex characters")
+	}
+	if _, err := hex.DecodeString(app.ID); err != nil {
+		return fmt.Errorf("application id must be 64 hex characters")
+	}
+
+	if app.Name == "" || len(app.Name) > 255 || len(app.Description) > 255 || len(app.URL) > 255 {
+		return fmt.Errorf("application name, description or url is invalid")
+	}
+
+	// A browser page can only speak for its own origin
+	if origin != "" {
+		u, err := url.Parse(app.URL)
+		if err != nil || u.Scheme+"://"+u.Host != origin {
+			return fmt.Errorf("application url does not match its origin %s", origin)
+		}
+	}
+
+	return nil
+}
+
+// Address that signed the application id, empty when the application is not signed
+func applicationSigner(app ApplicationData) (string, error) {
+	if len(app.Signature) == 0 {
+		return "", nil
+	}
+
+	var w walletapi.Wallet_Memory
+	signer, message, err := w.CheckSignature(app.Signature)
+	if err != nil {
+		return "", fmt.Errorf("application signature is invalid: %w", err)
+	}
+	if strings.TrimSpace(string(message)) != app.ID {
+		return "", fmt.Errorf("application signature is not for its id")
+	}
+
+	return signer.String(), nil
+}
+
+func (g *Gateway) serveWS(w http.ResponseWriter, r *http.Request) {
+	conn, err := xswdUpgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	conn.SetReadLimit(XSWD_MAX_MESSAGE)
+
+	var app ApplicationData
+	if err = conn.ReadJSON(&app); err != nil {
+		return
+	}
+
+	refuse := func(message string) {
+		conn.WriteJSON(AuthorizationResponse{Message: message})
+		g.record(AuditEntry{App: app.Name, ID: app.ID, Decision: "refused", Error: message})
+	}
+
+	origin := r.Header.Get("Origin")
+	if err = validApplication(app, origin); err != nil {
+		refuse(err.Error())
+		return
+	}
+
+	signer, err := applicationSigner(app)
+	if err != nil {
+		refuse(err.Error())
+		return
+	}
+
+	// Any program on this machine can send a registered name, url and Origin header, only
+	// the signature given at registration shows it is the same app. An unsigned app is
+	// asked for on every connection and request
+	verified := signer != ""
+
+	g.Lock()
+	if g.conns[app.ID] {
+		g.Unlock()
+		refuse("application is already connected")
+		return
+	}
+	known := g.apps[app.ID]
+	trusted := verified && known != nil && known.Name == app.Name && known.URL == app.URL && known.Signer == signer
+	g.Unlock()
+
+	if !trusted {
+		decision := g.ask(PermissionRequest{app: app, always: verified, verified: verified})
+		if !decision.allowed() {
+			refuse("connection denied by the user")
+			return
+		}
+
+		if decision == PERMISSION_ALWAYS_ALLOW {
+			trusted = true
+			g.Lock()
+			g.apps[app.ID] = &XSWDApp{ID: app.ID, Name: app.Name, Description: app.Description, URL: app.URL, Permissions: map[string]Permission{}, Signer: signer, Added: time.Now()}
+			if err := g.save(); err != nil {
+				globals.Logger.Error(err, "[Netrunner] Could not save gateway registry")
+			}
+			g.Unlock()
+		}
+	}
+
+	g.Lock()
+	if g.conns[app.ID] {
+		g.Unlock()
+		refuse("application is already connected")
+		return
+	}
+	g.conns[app.ID] = true
+	if known := g.apps[app.ID]; known != nil && trusted {
+		known.LastSeen = time.Now()
+	}
+	g.Unlock()
+
+	defer func() {
+		g.Lock()
+		delete(g.conns, app.ID)
+		g.Unlock()
+	}()
+
+	conn.WriteJSON(AuthorizationResponse{Message: "User has authorized the application", Accepted: true})
+	g.record(AuditEntry{App: app.Name, ID: app.ID, Decision: "connected"})
+	globals.Logger.Info("[Netrunner] dApp connected", "app", app.Name, "url", app.URL)
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+
+		if err = conn.WriteMessage(websocket.TextMessage, g.handle(app, trusted, message)); err != nil {
+			break
+		}
+	}
+
+	globals.Logger.Info("[Netrunner] dApp disconnected", "app", app.Name)
+}
+
+func rpcError(id json.RawMessage, code int, message string) []byte {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+
+	data, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"error":   map[string]interface{}{"code": code, "message": message},
+	})
+
+	return data
+}
+
+func rpcResult(id json.RawMessage, result interface{}) []byte {
+	data, _ := json.Marshal(map[string]interface{}{
+		"jsonrpc": "2.0",
+		"id":      id,
+		"result":  result,
+	})
+
+	return data
+}
+
+// Answer one request of a connected application, stored permissions apply only when trusted
+func (g *Gateway) handle(app ApplicationData, trusted bool, message []byte) []byte {
+	var req xswdRequest
+	if err := json.Unmarshal(message, &req); err != nil || req.Method == "" {
+		return rpcError(nil, -32700, "invalid request")
+	}
+
+	entry := AuditEntry{App: app.Name, ID: app.ID, Method: req.Method}
+
+	switch {
+	case req.Method == "HasMethod":
+		var p struct {
+			Name string `json:"name"`
+		}
+		json.Unmarshal(req.Params, &p)
+		return rpcResult(req.ID, xswdWalletMethods[p.Name] || strings.HasPrefix(p.Name, "DERO."))
+
+	case strings.HasPrefix(req.Method, "DERO."):
+		// Public chain data, no permission needed
+		if bw.chain == nil {
+			return rpcError(req.ID, -32000, "daemon is not running")
+		}
+
+		reply, err := forwardRPC(bw.rpc, "", message)
+		if err != nil {
+			return rpcError(req.ID, -32000, err.Error())
+		}
+		return reply
+
+	case !xswdWalletMethods[req.Method]:
+		return rpcError(req.ID, -32601, "method not found")
+	}
+
+	asked := dappWallet.get()
+
+	decision := g.permission(app, trusted, req)
+	entry.Decision = decision.String()
+	if !decision.allowed() {
+		g.record(entry)
+		return rpcError(req.ID, -32043, "permission denied")
+	}
+
+	// Held until the wallet RPC answered, closing the wallet waits for a transfer to be sent.
+	// It must be the wallet open when the user was asked
+	w := dappWallet.acquire()
+	if w == nil {
+		entry.Error = "no dApp wallet is open in Netrunner"
+		g.record(entry)
+		return rpcError(req.ID, -32000, entry.Error)
+	}
+	defer dappWallet.release()
+
+	if w != asked {
+		entry.Error = "dApp wallet was changed while the request waited"
+		g.record(entry)
+		return rpcError(req.ID, -32000, entry.Error)
+	}
+
+	// The wallet RPC has the methods under its own names
+	address, login, err := g.walletBackend(w)
+	if err != nil {
+		entry.Error = err.Error()
+		g.record(entry)
+		return rpcError(req.ID, -32000, err.Error())
+	}
+
+	reply, err := forwardRPC(address, login, message)
+	if err != nil {
+		entry.Error = err.Error()
+		g.record(entry)
+		return rpcError(req.ID, -32000, err.Error())
+	}
+
+	g.record(entry)
+
+	return reply
+}
+
+// Stored answers first, the user is asked for anything else
+func (g *Gateway) permission(app ApplicationData, trusted bool, req xswdRequest) Permission {
+	always := trusted && !xswdAlwaysAsk[req.Method]
+
+	g.Lock()
+	stored := PERMISSION_ASK
+	if known := g.apps[app.ID]; known != nil && always {
+		stored = known.Permissions[req.Method]
+	}
+	g.Unlock()
+
+	if stored == PERMISSION_ALWAYS_ALLOW || stored == PERMISSION_ALWAYS_DENY {
+		return stored
+	}
+
+	decision := g.ask(PermissionRequest{app: app, method: req.Method, params: describeParams(req.Method, req.Params), always: always, verified: trusted})
+
+	if always && (decision == PERMISSION_ALWAYS_ALLOW || decision == PERMISSION_ALWAYS_DENY) {
+		g.Lock()
+		if known := g.apps[app.ID]; known != nil {
+			known.Permissions[req.Method] = decision
+			if err := g.save(); err != nil {
+				globals.Logger.Error(err, "[Netrunner] Could not save gateway registry")
+			}
+		}
+		g.Unlock()
+	}
+
+	return decision
+}
+
+// A spend is confirmed on what the wallet will read from its params: every transfer, the
+// contract call, ring size and fees. Anything else is shown as sent, never cut short
+func describeParams(method string, params json.RawMessage) string {
+	var lines []string
+
+	switch method {
+	case "transfer", "Transfer":
+		var p derorpc.Transfer_Params
+		if err := json.Unmarshal(params, &p); err != nil {
+			break
+		}
+
+		lines = append(lines, describeTransfers(p.Transfers)...)
+		if p.SC_Code != "" {
+			lines = append(lines, fmt.Sprintf("Installs contract code: %d bytes", len(p.SC_Code)))
+		}
+		if p.SC_ID != "" {
+			lines = append(lines, "Contract: "+p.SC_ID)
+		}
+		lines = append(lines, describeArguments(p.SC_RPC)...)
+		lines = append(lines, describeRing(p.Ringsize), describeFees(p.Fees))
+
+	case "scinvoke":
+		var p derorpc.SC_Invoke_Params
+		if err := json.Unmarshal(params, &p); err != nil {
+			break
+		}
+
+		lines = append(lines, "Contract: "+p.SC_ID)
+		lines = append(lines, describeArguments(p.SC_RPC)...)
+		lines = append(lines, "Burn: "+globals.FormatMoney(p.SC_DERO_Deposit)+" DERO")
+		if p.SC_TOKEN_Deposit > 0 {
+			lines = append(lines, fmt.Sprintf("Token burn: %d", p.SC_TOKEN_Deposit))
+		}
+		lines = append(lines, describeRing(p.Ringsize), describeFees(0))
+	}
+
+	if lines != nil {
+		return strings.Join(lines, "\n")
+	}
+
+	var out bytes.Buffer
+	if err := json.Indent(&out, params, "", "  "); err != nil {
+		return string(params)
+	}
+
+	return out.String()
+}
+
+func describeTransfers(transfers []derorpc.Transfer) (lines []string) {
+	if len(transfers) == 0 {
+		return []string{"Transfers: none"}
+	}
+
+	for i, t := range transfers {
+		destination := t.Destination
+		if destination == "" {
+			destination = "a random ring member"
+		}
+
+		lines = append(lines, fmt.Sprintf("Transfer %d of %d", i+1, len(transfers)), "  To: "+destination)
+		if t.SCID.IsZero() {
+			lines = append(lines, "  Amount: "+globals.FormatMoney(t.Amount)+" DERO", "  Burn: "+globals.FormatMoney(t.Burn)+" DERO")
+		} else {
+			lines = append(lines, "  Token: "+t.SCID.String(), fmt.Sprintf("  Amount: %d", t.Amount), fmt.Sprintf("  Burn: %d", t.Burn))
+		}
+
+		for _, arg := range t.Payload_RPC {
+			lines = append(lines, fmt.Sprintf("  Payload %s: %v", arg.Name, arg.Value))
+		}
+	}
+
+	return
+}
+
+// Every argument as the contract receives it, the target and entrypoint named as such
+func describeArguments(args derorpc.Arguments) (lines []string) {
+	for _, arg := range args {
+		switch arg.Name {
+		case derorpc.SCID:
+			lines = append(lines, fmt.Sprintf("Contract: %v", arg.Value))
+		case "entrypoint":
+			lines = append(lines, fmt.Sprintf("Entrypoint: %v", arg.Value))
+		default:
+			lines = append(lines, fmt.Sprintf("Argument %s: %v", arg.Name, arg.Value))
+		}
+	}
+
+	return
+}
+
+func describeRing(ringsize uint64) string {
+	if ringsize == 0 {
+		return "Ring size: wallet default"
+	}
+
+	return fmt.Sprintf("Ring size: %d", ringsize)
+}
+
+func describeFees(fees uint64) string {
+	if fees == 0 {
+		return "Fees: set by the wallet"
+	}
+
+	return "Fees: " + globals.FormatMoney(fees) + " DERO"
+}
+
+// Put the request in front of the user, nobody answering is a refusal
+func (g *Gateway) ask(req PermissionRequest) Permission {
+	g.Lock()
+	prompt := g.prompt
+	g.Unlock()
+
+	if prompt == nil {
+		return PERMISSION_DENY
+	}
+
+	answer := make(chan Permission, 1)
+	go func() {
+		answer <- prompt(req)
+	}()
+
+	select {
+	case p := <-answer:
+		return p
+	case <-time.After(XSWD_PROMPT_TIMEOUT):
+		return PERMISSION_DENY
+	}
+}
+
+// Post a JSON-RPC request as is and hand back the raw reply
+func forwardRPC(address string, login string, body []byte) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodPost, "http://"+address+"/json_rpc", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if user, pass, ok := strings.Cut(login, ":"); ok {
+		req.SetBasicAuth(user, pass)
+	}
+
+	resp, err := xswdClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("RPC returned %s", resp.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(resp.Body, XSWD_MAX_MESSAGE))
+}